@@ -5,6 +5,7 @@ go 1.23.0
 toolchain go1.24.5
 
 require (
-	golang.org/x/sys v0.34.0 // indirect
-	golang.org/x/term v0.33.0 // indirect
+	golang.org/x/image v0.29.0
+	golang.org/x/sys v0.34.0
+	golang.org/x/term v0.33.0
 )
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"anifetch/pkg/anime"
 	"anifetch/pkg/config"
 	"anifetch/pkg/display"
+	"anifetch/pkg/logo"
 	"anifetch/pkg/system"
 )
 
@@ -19,15 +25,70 @@ func main() {
 		showCache = flag.Bool("show-cache", false, "Show cached images")
 		checkToken = flag.Bool("check-token", false, "Check GitHub token status")
 		imageSize = flag.String("size", "40x20", "Image size (fallback if terminal size detection fails)")
+		backend = flag.String("backend", "auto", "Image backend: "+strings.Join(display.Backends, ", "))
+		asciiColor = flag.Bool("ascii-color", true, "Color the ASCII ramp image backend")
+		colorMode = flag.String("color", "auto", "Use color: "+strings.Join(display.ColorModes, ", ")+" (auto honours NO_COLOR and CLICOLOR_FORCE)")
+		themeName = flag.String("theme", "default", "Color theme: a built-in name, a theme in the themes directory, a JSON file, or auto to match the image")
+		modules = flag.String("modules", "", "Comma-separated info modules to show, in order (overrides the config file)")
+		timings = flag.Bool("timings", false, "Print how long each info module took")
+		moduleTimeout = flag.Duration("module-timeout", 500*time.Millisecond, "Give up on an info module after this long")
+		timeout = flag.Duration("timeout", time.Second, "Give up on all remaining info modules after this long")
+		logoFlag = flag.String("logo", "image", "What to show above the info: image, distro (ASCII distro logo), a built-in logo name, or a neofetch-style logo file")
+		bars = flag.Bool("bars", false, "Draw usage bars after memory, disk and battery")
+		jsonOutput = flag.Bool("json", false, "Print the collected info as JSON instead of displaying it")
+		export = flag.String("export", "", "Write the output to a file instead of the terminal ("+strings.Join(display.ExportFormats, ", ")+")")
 	)
 	flag.Parse()
 
+	if !oneOf(*backend, display.Backends) {
+		fmt.Fprintf(os.Stderr, "Unknown backend %q (choose from %s)\n", *backend, strings.Join(display.Backends, ", "))
+		os.Exit(2)
+	}
+	if !oneOf(*colorMode, display.ColorModes) {
+		fmt.Fprintf(os.Stderr, "Unknown color mode %q (choose from %s)\n", *colorMode, strings.Join(display.ColorModes, ", "))
+		os.Exit(2)
+	}
+
 	// Initialize configuration
 	cfg := config.NewConfig()
 	
 	// Initialize display renderer with custom image size
 	renderer := display.NewRenderer(!*noImage && cfg.ShowImage)
+	renderer.SetColorMode(*colorMode)
 	renderer.SetImageSize(*imageSize)
+	renderer.SetBackend(*backend)
+	renderer.SetASCIIColor(*asciiColor)
+
+	if err := cfg.Load(); err != nil {
+		renderer.DisplayError(fmt.Sprintf("Failed to load config: %v", err))
+		os.Exit(1)
+	}
+
+	// The auto theme is derived once the image is known
+	if *themeName != "auto" {
+		theme, err := display.LoadTheme(*themeName, cfg.GetThemesDir())
+		if err != nil {
+			renderer.DisplayError(fmt.Sprintf("Failed to load theme: %v", err))
+			os.Exit(1)
+		}
+		renderer.SetTheme(theme)
+	}
+
+	showBars := *bars || cfg.Bar.Enabled
+	if showBars {
+		renderer.SetBar(display.Bar{
+			Width:    cfg.Bar.Width,
+			Full:     cfg.Bar.Full,
+			Empty:    cfg.Bar.Empty,
+			Warn:     cfg.Bar.Warn,
+			Critical: cfg.Bar.Critical,
+		})
+	}
+
+	// Handle subcommands
+	if flag.Arg(0) == "themes" {
+		os.Exit(runThemes(renderer, cfg, flag.Args()[1:]))
+	}
 
 	// Handle special commands
 	if *clearCache {
@@ -79,11 +140,94 @@ func main() {
 	}
 
 	// Get system information
-	sysInfo := system.GetSystemInfo()
+	if !oneOf(cfg.Units, system.Units) {
+		renderer.DisplayError(fmt.Sprintf("Unknown units %q in config (choose from %s)", cfg.Units, strings.Join(system.Units, ", ")))
+		os.Exit(2)
+	}
+	system.SetCacheDir(cfg.GetCacheDir())
+	// The bar shows the percentage, so the text need not repeat it
+	system.SetFormat(system.Format{Unit: cfg.Units, SI: cfg.SIUnits, Percent: cfg.ShowPercent && !showBars})
+	system.SetDiskMounts(cfg.DiskMounts)
+	if cfg.PublicIP.URL != "" {
+		resolver := system.NewHTTPResolver(cfg.PublicIP.URL, time.Duration(cfg.PublicIP.TimeoutMS)*time.Millisecond)
+		system.SetPublicIPResolver(system.NewCachingResolver(resolver, filepath.Join(cfg.GetCacheDir(), "public-ip.json"), time.Duration(cfg.PublicIP.CacheMinutes)*time.Minute))
+	}
+	if cfg.FailedUnits {
+		system.SetFailedUnitCounter(system.NewSystemctlCounter(300 * time.Millisecond))
+	}
+	moduleNames := cfg.Modules
+	if *modules != "" {
+		moduleNames = strings.Split(*modules, ",")
+	}
+	if len(moduleNames) == 0 {
+		moduleNames = system.DefaultModules
+	}
+	for _, name := range moduleNames {
+		if _, ok := system.Lookup(name); !ok {
+			renderer.DisplayError(fmt.Sprintf("Unknown module %q (available: %s)", name, strings.Join(system.ModuleNames(), ", ")))
+			os.Exit(2)
+		}
+	}
+	report := system.Collect(context.Background(), moduleNames, system.Options{
+		Labels:        cfg.Labels,
+		ModuleTimeout: *moduleTimeout,
+		Timeout:       *timeout,
+	})
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			renderer.DisplayError(fmt.Sprintf("Failed to encode JSON: %v", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Pick the ASCII logo, used as a fallback for the image or instead of it
+	// Reuse the terminal module's answer when it ran; otherwise the
+	// renderer detects the terminal only if it needs to pick a backend
+	if t, ok := report.Data("terminal").(system.Terminal); ok {
+		renderer.SetTerminal(t.Name)
+	}
+
+	distro, ok := report.Data("os").(system.Distro)
+	if !ok {
+		// The os module was not shown or timed out; detect it within the
+		// same time limit
+		ctx := context.Background()
+		if *moduleTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *moduleTimeout)
+			defer cancel()
+		}
+		distro = system.DetectDistro(ctx)
+	}
+	distroLogo := logo.ForDistro(distro.IDs())
+	switch *logoFlag {
+	case "image":
+		renderer.SetLogo(distroLogo, false)
+	case "distro":
+		renderer.SetLogo(distroLogo, true)
+	default:
+		userLogo, ok := logo.Get(*logoFlag)
+		if !ok {
+			var err error
+			if userLogo, err = logo.Load(*logoFlag); err != nil {
+				renderer.DisplayError(fmt.Sprintf("Failed to load logo: %v", err))
+				os.Exit(1)
+			}
+			if len(userLogo.Colors) == 0 {
+				// Like neofetch, custom logos borrow the distro's colors
+				userLogo.Colors = distroLogo.Colors
+			}
+		}
+		renderer.SetLogo(userLogo, true)
+	}
 
 	// Get anime girl image
 	var animeGirlPath string
-	if !*noImage {
+	if !*noImage && *logoFlag == "image" {
 		fetcher := anime.NewFetcher(cfg.GetCacheDir())
 		path, err := fetcher.GetRandomAnimeGirl()
 		if err != nil {
@@ -94,6 +238,69 @@ func main() {
 		}
 	}
 
+	if *themeName == "auto" && animeGirlPath != "" {
+		if theme, err := display.AutoTheme(animeGirlPath, display.DarkBackground()); err == nil {
+			renderer.SetTheme(theme)
+		}
+	}
+
+	if *export != "" {
+		if err := display.Export(*export, display.InfoLines(report, renderer.Theme(), renderer.Bar()), animeGirlPath); err != nil {
+			renderer.DisplayError(fmt.Sprintf("Failed to export: %v", err))
+			os.Exit(1)
+		}
+		renderer.DisplaySuccess(fmt.Sprintf("Exported to %s", *export))
+		return
+	}
+
 	// Display the information
-	renderer.DisplayInfo(sysInfo, animeGirlPath)
-} 
\ No newline at end of file
+	renderer.DisplayInfo(report, animeGirlPath)
+	if *timings {
+		renderer.DisplayTimings(report)
+	}
+}
+
+// runThemes implements "anifetch themes list" and
+// "anifetch themes preview [name...]".
+func runThemes(renderer *display.Renderer, cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: anifetch themes list|preview [name...]")
+		return 2
+	}
+
+	names := display.ThemeNames(cfg.GetThemesDir())
+	switch args[0] {
+	case "list":
+		for _, name := range names {
+			fmt.Println(name)
+		}
+	case "preview":
+		if len(args) > 1 {
+			names = args[1:]
+		}
+		for i, name := range names {
+			theme, err := display.LoadTheme(name, cfg.GetThemesDir())
+			if err != nil {
+				renderer.DisplayError(fmt.Sprintf("Failed to load theme: %v", err))
+				return 1
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			renderer.PreviewTheme(theme)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown themes command %q\n", args[0])
+		return 2
+	}
+	return 0
+}
+
+func oneOf(value string, choices []string) bool {
+	for _, c := range choices {
+		if c == value {
+			return true
+		}
+	}
+	return false
+}
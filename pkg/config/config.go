@@ -1,15 +1,71 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 )
 
 type Config struct {
 	CacheDir   string
+	ConfigDir  string
 	ShowImage  bool
 	ImageWidth int
 	ImageHeight int
+
+	// Modules lists info modules in display order; empty means the default
+	Modules []string
+	// Labels overrides the display label of modules by name
+	Labels map[string]string
+	// Units is the byte unit for memory and disk: auto, MiB or GiB
+	Units string
+	// SIUnits uses powers of 1000 instead of 1024
+	SIUnits bool
+	// ShowPercent appends the used percentage to memory and disk
+	ShowPercent bool
+	// DiskMounts lists the mount points shown by the disk module; empty
+	// means every real filesystem
+	DiskMounts []string
+	// Bar configures usage bars after memory, disk and battery
+	Bar BarConfig
+	// PublicIP configures the lookup behind the publicip module
+	PublicIP PublicIPConfig
+	// FailedUnits makes the init module count failed systemd units
+	FailedUnits bool
+}
+
+// BarConfig is the "bar" section of config.json.
+type BarConfig struct {
+	Enabled  bool    `json:"enabled"`
+	Width    int     `json:"width"`
+	Full     string  `json:"full"`
+	Empty    string  `json:"empty"`
+	Warn     float64 `json:"warn"`
+	Critical float64 `json:"critical"`
+}
+
+// PublicIPConfig is the "public_ip" section of config.json.
+type PublicIPConfig struct {
+	// URL answers with the caller's address as plain text; empty disables
+	// the lookup
+	URL          string `json:"url"`
+	TimeoutMS    int    `json:"timeout_ms"`
+	CacheMinutes int    `json:"cache_minutes"`
+}
+
+// fileConfig is the layout of config.json.
+type fileConfig struct {
+	Modules []string          `json:"modules"`
+	Labels  map[string]string `json:"labels"`
+	Units   string            `json:"units"`
+	SIUnits bool              `json:"si_units"`
+	// A pointer so that an omitted key keeps the default
+	ShowPercent *bool          `json:"show_percent"`
+	DiskMounts  []string       `json:"disk_mounts"`
+	Bar         BarConfig      `json:"bar"`
+	PublicIP    PublicIPConfig `json:"public_ip"`
+	FailedUnits bool           `json:"failed_units"`
 }
 
 func NewConfig() *Config {
@@ -19,15 +75,62 @@ func NewConfig() *Config {
 	}
 	
 	cacheDir := filepath.Join(homeDir, ".anifetch")
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = filepath.Join(homeDir, ".config")
+	}
 	
 	return &Config{
 		CacheDir:   cacheDir,
+		ConfigDir:  filepath.Join(configDir, "anifetch"),
 		ShowImage:  true,
 		ImageWidth: 200,
 		ImageHeight: 200,
+		Units:       "auto",
+		ShowPercent: true,
+		Bar:         BarConfig{Width: 10, Full: "█", Empty: "░", Warn: 70, Critical: 90},
+		PublicIP:    PublicIPConfig{URL: "https://api.ipify.org", TimeoutMS: 400, CacheMinutes: 30},
 	}
 }
 
+// GetConfigFile returns the path of the user's config file.
+func (c *Config) GetConfigFile() string {
+	return filepath.Join(c.ConfigDir, "config.json")
+}
+
+// Load reads the config file if there is one. A missing file is not an
+// error.
+func (c *Config) Load() error {
+	data, err := os.ReadFile(c.GetConfigFile())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading config: %v", err)
+	}
+
+	// Keys missing from the bar and public_ip sections keep their defaults
+	file := fileConfig{Bar: c.Bar, PublicIP: c.PublicIP}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("error parsing %s: %v", c.GetConfigFile(), err)
+	}
+	c.Modules = file.Modules
+	c.Labels = file.Labels
+	if file.Units != "" {
+		c.Units = file.Units
+	}
+	c.SIUnits = file.SIUnits
+	if file.ShowPercent != nil {
+		c.ShowPercent = *file.ShowPercent
+	}
+	c.DiskMounts = file.DiskMounts
+	c.Bar = file.Bar
+	c.PublicIP = file.PublicIP
+	c.FailedUnits = file.FailedUnits
+	return nil
+}
+
 func (c *Config) EnsureCacheDir() error {
 	return os.MkdirAll(c.CacheDir, 0755)
 }
@@ -36,6 +139,11 @@ func (c *Config) GetCacheDir() string {
 	return c.CacheDir
 }
 
+// GetThemesDir returns the directory searched for user theme files.
+func (c *Config) GetThemesDir() string {
+	return filepath.Join(c.ConfigDir, "themes")
+}
+
 func (c *Config) SetShowImage(show bool) {
 	c.ShowImage = show
 }
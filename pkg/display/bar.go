@@ -0,0 +1,74 @@
+package display
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"anifetch/pkg/system"
+)
+
+// Bar describes the usage bar drawn after percentage-valued modules such
+// as memory, disk and battery.
+type Bar struct {
+	// Width is the number of cells between the brackets; zero disables bars
+	Width int
+	// Full and Empty fill the used and unused cells
+	Full  string
+	Empty string
+	// Warn and Critical are the percentages from which the bar uses the
+	// bar-warn and bar-critical theme roles instead of bar-ok
+	Warn     float64
+	Critical float64
+}
+
+// asciiBar replaces non-ASCII bar characters where the terminal is unlikely
+// to render them.
+func asciiBar(b Bar) Bar {
+	if supportsUnicode() {
+		return b
+	}
+	if !isASCII(b.Full) || !isASCII(b.Empty) {
+		b.Full, b.Empty = "#", "-"
+	}
+	return b
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// barSegments renders data as "[██████░░░░] 49%" if it is a percentage,
+// or returns nil.
+func barSegments(data any, bar Bar, theme *Theme) Line {
+	p, ok := data.(system.Percentage)
+	if !ok || bar.Width <= 0 {
+		return nil
+	}
+	percent := math.Max(0, math.Min(100, p.Percentage()))
+
+	role := RoleBarOK
+	switch {
+	case percent >= bar.Critical:
+		role = RoleBarCritical
+	case percent >= bar.Warn:
+		role = RoleBarWarn
+	}
+	fill := theme.Style(role)
+	delimiter := theme.Style(RoleDelimiter)
+
+	full := int(math.Round(percent / 100 * float64(bar.Width)))
+	return Line{
+		{"[", delimiter},
+		{strings.Repeat(bar.Full, full), fill},
+		{strings.Repeat(bar.Empty, bar.Width-full), theme.Style(RoleSeparator)},
+		{"]", delimiter},
+		{fmt.Sprintf(" %.0f%%", percent), fill},
+	}
+}
@@ -0,0 +1,79 @@
+package display
+
+import (
+	"testing"
+
+	"anifetch/pkg/system"
+)
+
+func TestBarSegments(t *testing.T) {
+	theme := DefaultTheme()
+	bar := Bar{Width: 10, Full: "#", Empty: "-", Warn: 70, Critical: 90}
+	tests := []struct {
+		name string
+		data any
+		bar  Bar
+		want string
+		role Role
+	}{
+		{"empty", system.Percent(0), bar, "[----------] 0%", RoleBarOK},
+		{"below warn", system.Percent(69), bar, "[#######---] 69%", RoleBarOK},
+		{"warn", system.Percent(70), bar, "[#######---] 70%", RoleBarWarn},
+		{"critical", system.Percent(90), bar, "[#########-] 90%", RoleBarCritical},
+		{"full", system.Percent(100), bar, "[##########] 100%", RoleBarCritical},
+		{"clamped", system.Percent(130), bar, "[##########] 100%", RoleBarCritical},
+		{"rounded", system.Percent(44), bar, "[####------] 44%", RoleBarOK},
+		{"usage", system.NewUsage(1, 4), Bar{Width: 4, Full: "█", Empty: "░", Warn: 70, Critical: 90}, "[█░░░] 25%", RoleBarOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := barSegments(tt.data, tt.bar, theme)
+			if got := line.Plain(); got != tt.want {
+				t.Errorf("barSegments() = %q, want %q", got, tt.want)
+			}
+			if got := line[len(line)-1].Style; got != theme.Style(tt.role) {
+				t.Errorf("percentage style = %+v, want the %s style %+v", got, tt.role, theme.Style(tt.role))
+			}
+		})
+	}
+}
+
+func TestBarSegmentsDisabled(t *testing.T) {
+	theme := DefaultTheme()
+	if line := barSegments(system.Percent(50), Bar{Width: 0, Full: "#", Empty: "-"}, theme); line != nil {
+		t.Errorf("barSegments() with zero width = %q, want nil", line.Plain())
+	}
+	if line := barSegments("7.64 GiB / 15.52 GiB", Bar{Width: 10, Full: "#", Empty: "-"}, theme); line != nil {
+		t.Errorf("barSegments() of non-percentage data = %q, want nil", line.Plain())
+	}
+	if line := barSegments(nil, Bar{Width: 10, Full: "#", Empty: "-"}, theme); line != nil {
+		t.Errorf("barSegments() of nil data = %q, want nil", line.Plain())
+	}
+}
+
+func TestASCIIBar(t *testing.T) {
+	blocks := Bar{Width: 10, Full: "█", Empty: "░"}
+	hashes := Bar{Width: 10, Full: "=", Empty: " "}
+
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_CTYPE", "")
+	t.Setenv("LANG", "en_US.UTF-8")
+	if got := asciiBar(blocks); got != blocks {
+		t.Errorf("asciiBar() on a UTF-8 terminal = %+v, want it unchanged", got)
+	}
+
+	t.Setenv("LANG", "C")
+	if got := asciiBar(blocks); got.Full != "#" || got.Empty != "-" || got.Width != 10 {
+		t.Errorf("asciiBar() without UTF-8 = %+v, want # and -", got)
+	}
+	if got := asciiBar(hashes); got != hashes {
+		t.Errorf("asciiBar() of an ASCII bar = %+v, want it unchanged", got)
+	}
+
+	t.Setenv("LANG", "en_US.UTF-8")
+	t.Setenv("TERM", "linux")
+	if got := asciiBar(blocks); got.Full != "#" {
+		t.Errorf("asciiBar() on the Linux console = %+v, want ASCII", got)
+	}
+}
@@ -0,0 +1,257 @@
+package display
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// ExportFormats lists the file extensions accepted by Export.
+var ExportFormats = []string{".png", ".svg", ".html"}
+
+const (
+	exportPadding   = 16
+	exportMinHeight = 240
+	exportMaxWidth  = 400
+)
+
+var exportBackground = color.RGBA{0x1e, 0x1e, 0x1e, 0xff}
+
+// Export writes a snapshot of the info lines next to the image at
+// imagePath, which may be empty. The format is chosen by path's extension.
+// It never touches the terminal, so it works in headless environments.
+func Export(path string, lines []Line, imagePath string) error {
+	var data []byte
+	var img image.Image
+	if imagePath != "" {
+		var err error
+		if data, err = os.ReadFile(imagePath); err != nil {
+			return fmt.Errorf("error reading image: %v", err)
+		}
+		if img, _, err = image.Decode(bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("error decoding image: %v", err)
+		}
+	}
+
+	var out []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		out, err = exportPNG(lines, img)
+	case ".svg":
+		out = exportSVG(lines, img, data)
+	case ".html", ".htm":
+		out = exportHTML(lines, img, data)
+	default:
+		return fmt.Errorf("unsupported export format %q (use %s)", filepath.Ext(path), strings.Join(ExportFormats, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("error writing export: %v", err)
+	}
+	return nil
+}
+
+// imageBox returns the size the image is drawn at so that it is at least
+// as tall as the text block while staying within exportMaxWidth.
+func imageBox(img image.Image, textHeight int) (int, int) {
+	if img == nil {
+		return 0, 0
+	}
+	b := img.Bounds()
+	if b.Dx() == 0 || b.Dy() == 0 {
+		return 0, 0
+	}
+
+	height := textHeight
+	if height < exportMinHeight {
+		height = exportMinHeight
+	}
+	width := height * b.Dx() / b.Dy()
+	if width > exportMaxWidth {
+		width = exportMaxWidth
+		height = width * b.Dy() / b.Dx()
+	}
+	return width, height
+}
+
+func maxLineLength(lines []Line) int {
+	longest := 0
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(line.Plain()); n > longest {
+			longest = n
+		}
+	}
+	return longest
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func dataURI(data []byte) string {
+	return "data:" + http.DetectContentType(data) + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+func exportPNG(lines []Line, img image.Image) ([]byte, error) {
+	face := basicfont.Face7x13
+	charWidth := face.Advance
+	lineHeight := face.Height + 2
+
+	textWidth := maxLineLength(lines) * charWidth
+	textHeight := len(lines) * lineHeight
+	imgWidth, imgHeight := imageBox(img, textHeight)
+
+	width := exportPadding*2 + textWidth
+	if imgWidth > 0 {
+		width += imgWidth + exportPadding
+	}
+	height := exportPadding*2 + max(textHeight, imgHeight)
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(exportBackground), image.Point{}, draw.Src)
+
+	textX := exportPadding
+	if imgWidth > 0 {
+		dst := image.Rect(exportPadding, exportPadding, exportPadding+imgWidth, exportPadding+imgHeight)
+		draw.CatmullRom.Scale(canvas, dst, img, img.Bounds(), draw.Over, nil)
+		textX += imgWidth + exportPadding
+	}
+
+	for i, line := range lines {
+		x := textX
+		y := exportPadding + i*lineHeight + face.Ascent
+		for _, seg := range line {
+			d := &font.Drawer{
+				Dst:  canvas,
+				Src:  image.NewUniform(seg.Style.RGB()),
+				Face: face,
+				Dot:  fixed.P(x, y),
+			}
+			text := strings.Map(asciiGlyph, seg.Text)
+			d.DrawString(text)
+			if seg.Style.Bold {
+				// The bitmap font has no bold variant, so overstrike
+				d.Dot = fixed.P(x+1, y)
+				d.DrawString(text)
+			}
+			x += utf8.RuneCountInString(seg.Text) * charWidth
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, fmt.Errorf("error encoding PNG: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// asciiGlyph substitutes characters missing from the bitmap font, which
+// only covers printable ASCII.
+func asciiGlyph(r rune) rune {
+	if r >= 0x20 && r < 0x7f {
+		return r
+	}
+	switch r {
+	case '─', '━', '═':
+		return '-'
+	case '│', '┃', '║':
+		return '|'
+	case '█', '▓', '▒':
+		return '#'
+	case '░':
+		return '.'
+	}
+	return '?'
+}
+
+// SVG and HTML text metrics for a 14px monospace font.
+const (
+	docFontSize   = 14
+	docCharWidth  = 8.4
+	docLineHeight = 18
+)
+
+func exportSVG(lines []Line, img image.Image, data []byte) []byte {
+	textWidth := int(float64(maxLineLength(lines))*docCharWidth + 0.5)
+	textHeight := len(lines) * docLineHeight
+	imgWidth, imgHeight := imageBox(img, textHeight)
+
+	width := exportPadding*2 + textWidth
+	textX := exportPadding
+	if imgWidth > 0 {
+		width += imgWidth + exportPadding
+		textX += imgWidth + exportPadding
+	}
+	height := exportPadding*2 + max(textHeight, imgHeight)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	fmt.Fprintf(&sb, "  <rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", hexColor(exportBackground))
+	if imgWidth > 0 {
+		fmt.Fprintf(&sb, "  <image x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" href=\"%s\"/>\n",
+			exportPadding, exportPadding, imgWidth, imgHeight, dataURI(data))
+	}
+
+	fmt.Fprintf(&sb, "  <g font-family=\"monospace\" font-size=\"%d\">\n", docFontSize)
+	for i, line := range lines {
+		y := exportPadding + i*docLineHeight + docFontSize
+		fmt.Fprintf(&sb, "    <text x=\"%d\" y=\"%d\" xml:space=\"preserve\">", textX, y)
+		for _, seg := range line {
+			weight := ""
+			if seg.Style.Bold {
+				weight = " font-weight=\"bold\""
+			}
+			fmt.Fprintf(&sb, "<tspan fill=\"%s\"%s>%s</tspan>", hexColor(seg.Style.RGB()), weight, html.EscapeString(seg.Text))
+		}
+		sb.WriteString("</text>\n")
+	}
+	sb.WriteString("  </g>\n</svg>\n")
+	return []byte(sb.String())
+}
+
+func exportHTML(lines []Line, img image.Image, data []byte) []byte {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>anifetch</title>\n</head>\n")
+	fmt.Fprintf(&sb, "<body style=\"margin:0;background:%s\">\n", hexColor(exportBackground))
+	fmt.Fprintf(&sb, "<div style=\"display:flex;align-items:flex-start;gap:%dpx;padding:%dpx\">\n", exportPadding, exportPadding)
+
+	if img != nil {
+		imgWidth, imgHeight := imageBox(img, len(lines)*docLineHeight)
+		fmt.Fprintf(&sb, "<img src=\"%s\" width=\"%d\" height=\"%d\" alt=\"\">\n", dataURI(data), imgWidth, imgHeight)
+	}
+
+	fmt.Fprintf(&sb, "<pre style=\"margin:0;font-family:monospace;font-size:%dpx;line-height:%dpx;color:%s\">",
+		docFontSize, docLineHeight, hexColor(defaultForeground))
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		for _, seg := range line {
+			css := "color:" + hexColor(seg.Style.RGB())
+			if seg.Style.Bold {
+				css += ";font-weight:bold"
+			}
+			fmt.Fprintf(&sb, "<span style=\"%s\">%s</span>", css, html.EscapeString(seg.Text))
+		}
+	}
+	sb.WriteString("</pre>\n</div>\n</body>\n</html>\n")
+	return []byte(sb.String())
+}
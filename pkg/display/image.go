@@ -2,48 +2,101 @@ package display
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"strings"
+
 	"golang.org/x/term"
 )
 
 // ImageDisplay handles different methods of displaying images in terminal
 type ImageDisplay struct{
-	size string
+	size       string
+	backend    string
+	asciiColor bool
+	out        io.Writer
+	graphics   bool
+	terminal   string
 }
 
+// Backends lists the accepted values for SetBackend.
+var Backends = []string{"auto", "chafa", "imgcat", "kitty", "braille", "ascii"}
+
 func NewImageDisplay() *ImageDisplay {
-	return &ImageDisplay{size: "15x8"}
+	return &ImageDisplay{size: "15x8", backend: "auto", asciiColor: true, out: os.Stdout, graphics: true}
 }
 
 func NewImageDisplayWithSize(size string) *ImageDisplay {
-	return &ImageDisplay{size: size}
+	return &ImageDisplay{size: size, backend: "auto", asciiColor: true, out: os.Stdout, graphics: true}
+}
+
+// SetBackend restricts display to a single method; "auto" tries them all.
+func (id *ImageDisplay) SetBackend(backend string) {
+	id.backend = backend
+}
+
+// SetASCIIColor toggles per-character color in the ASCII ramp renderer.
+func (id *ImageDisplay) SetASCIIColor(color bool) {
+	id.asciiColor = color
+}
+
+// SetOutput directs all image output to out. When out does not accept
+// terminal graphics only the text backends are used.
+func (id *ImageDisplay) SetOutput(out *Output) {
+	id.out = out.Writer()
+	id.graphics = out.Graphics()
+}
+
+// SetTerminal tells auto selection which terminal emulator is in use, so
+// its native image protocol is tried first.
+func (id *ImageDisplay) SetTerminal(name string) {
+	id.terminal = name
 }
 
 func (id *ImageDisplay) DisplayImage(imagePath string) bool {
-	// Try different image display methods in order of preference
-	
-	// 1. Try chafa (modern terminal image viewer)
-	if id.tryChafa(imagePath) {
-		return true
+	if !id.graphics {
+		// Never send graphics escape sequences to a pipe or file
+		if id.backend == "ascii" || !supportsUnicode() {
+			return id.tryASCII(imagePath)
+		}
+		return id.tryBraille(imagePath)
 	}
-	
-	// 2. Try imgcat (iTerm2 image protocol)
-	if id.tryImgcat(imagePath) {
-		return true
+
+	switch id.backend {
+	case "chafa":
+		return id.tryChafa(imagePath)
+	case "imgcat":
+		return id.tryImgcat(imagePath)
+	case "kitty":
+		return id.tryKittyIcat(imagePath)
+	case "braille":
+		return id.tryBraille(imagePath)
+	case "ascii":
+		return id.tryASCII(imagePath)
 	}
-	
-	// 3. Try kitty icat
-	if id.tryKittyIcat(imagePath) {
-		return true
+
+	// Try different image display methods in order of preference:
+	// chafa (modern terminal image viewer), imgcat (iTerm2 image protocol)
+	// and kitty icat, moving the terminal's native protocol to the front
+	methods := []func(string) bool{id.tryChafa, id.tryImgcat, id.tryKittyIcat}
+	switch id.terminal {
+	case "kitty", "Ghostty":
+		methods = []func(string) bool{id.tryKittyIcat, id.tryChafa, id.tryImgcat}
+	case "iTerm2", "WezTerm":
+		methods = []func(string) bool{id.tryImgcat, id.tryChafa, id.tryKittyIcat}
 	}
-	
-	// 4. Try terminal image protocols
-	if id.tryTerminalProtocols(imagePath) {
-		return true
+	for _, try := range methods {
+		if try(imagePath) {
+			return true
+		}
 	}
 	
-	return false
+	// Render the image as text, using braille where Unicode is available
+	if supportsUnicode() {
+		return id.tryBraille(imagePath)
+	}
+	return id.tryASCII(imagePath)
 }
 
 func (id *ImageDisplay) tryChafa(imagePath string) bool {
@@ -83,7 +136,7 @@ func (id *ImageDisplay) tryChafa(imagePath string) bool {
 		"--colors", "256",
 		"--dither", "none",
 		imagePath)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = id.out
 	cmd.Stderr = os.Stderr
 	
 	if err := cmd.Run(); err == nil {
@@ -97,7 +150,7 @@ func (id *ImageDisplay) tryChafa(imagePath string) bool {
 		"--colors", "256",
 		"--dither", "none",
 		imagePath)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = id.out
 	cmd.Stderr = os.Stderr
 	
 	if err := cmd.Run(); err == nil {
@@ -111,7 +164,7 @@ func (id *ImageDisplay) tryChafa(imagePath string) bool {
 		"--colors", "256",
 		"--dither", "none",
 		imagePath)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = id.out
 	cmd.Stderr = os.Stderr
 	
 	if err := cmd.Run(); err == nil {
@@ -125,7 +178,7 @@ func (id *ImageDisplay) tryChafa(imagePath string) bool {
 		"--colors", "256",
 		"--dither", "none",
 		imagePath)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = id.out
 	cmd.Stderr = os.Stderr
 	
 	if err := cmd.Run(); err == nil {
@@ -137,7 +190,7 @@ func (id *ImageDisplay) tryChafa(imagePath string) bool {
 
 func (id *ImageDisplay) tryImgcat(imagePath string) bool {
 	cmd := exec.Command("imgcat", imagePath)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = id.out
 	cmd.Stderr = os.Stderr
 	
 	if err := cmd.Run(); err == nil {
@@ -148,7 +201,7 @@ func (id *ImageDisplay) tryImgcat(imagePath string) bool {
 
 func (id *ImageDisplay) tryKittyIcat(imagePath string) bool {
 	cmd := exec.Command("kitty", "+kitten", "icat", imagePath)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = id.out
 	cmd.Stderr = os.Stderr
 	
 	if err := cmd.Run(); err == nil {
@@ -157,19 +210,33 @@ func (id *ImageDisplay) tryKittyIcat(imagePath string) bool {
 	return false
 }
 
-func (id *ImageDisplay) tryTerminalProtocols(imagePath string) bool {
-	// Instead of trying terminal protocols that often don't work well,
-	// show a nice ASCII art fallback
-	fmt.Println("╭─────────────────────────╮")
-	fmt.Println("│      (◕‿◕)            │")
-	fmt.Println("│       /|\\             │")
-	fmt.Println("│      / \\              │")
-	fmt.Println("│                        │")
-	fmt.Println("│  Holding a Programming │")
-	fmt.Println("│      Book! 📚          │")
-	fmt.Println("│                        │")
-	fmt.Println("│  🎀 Anime Girl 🎀      │")
-	fmt.Println("╰─────────────────────────╯")
+func (id *ImageDisplay) tryBraille(imagePath string) bool {
+	img, err := loadImage(imagePath)
+	if err != nil {
+		return false
+	}
+
+	cols, rows := parseSize(id.size)
+	lines := RenderBraille(img, cols, rows, -1)
+	if len(lines) == 0 {
+		return false
+	}
+	fmt.Fprintln(id.out, strings.Join(lines, "\n"))
+	return true
+}
+
+func (id *ImageDisplay) tryASCII(imagePath string) bool {
+	img, err := loadImage(imagePath)
+	if err != nil {
+		return false
+	}
+
+	cols, rows := parseSize(id.size)
+	lines := RenderASCII(img, cols, rows, id.asciiColor)
+	if len(lines) == 0 {
+		return false
+	}
+	fmt.Fprintln(id.out, strings.Join(lines, "\n"))
 	return true
 }
 
@@ -0,0 +1,140 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ColorModes lists the accepted values for the --color flag.
+var ColorModes = []string{"auto", "always", "never"}
+
+// Output is where the renderer writes. It decides once whether styling is
+// wanted and strips escape sequences from everything written through it
+// when it is not.
+type Output struct {
+	w        io.Writer
+	color    bool
+	terminal bool
+	strip    stripState
+}
+
+// NewOutput wraps f using the given color mode. In "auto" mode color is
+// enabled for terminals unless NO_COLOR is set or TERM is "dumb", and
+// CLICOLOR_FORCE enables it even when f is not a terminal.
+func NewOutput(f *os.File, mode string) *Output {
+	terminal := term.IsTerminal(int(f.Fd()))
+	return &Output{w: f, color: useColor(mode, terminal), terminal: terminal}
+}
+
+func useColor(mode string, terminal bool) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	return terminal && os.Getenv("TERM") != "dumb"
+}
+
+// Color reports whether styled output is enabled.
+func (o *Output) Color() bool {
+	return o.color
+}
+
+// Graphics reports whether terminal image sequences may be written. They
+// are only useful on a terminal that also accepts color.
+func (o *Output) Graphics() bool {
+	return o.terminal && o.color
+}
+
+// Writer returns the destination to hand to child processes. With color
+// enabled no filtering is needed, so the underlying file is returned and
+// tools like chafa can still detect the terminal.
+func (o *Output) Writer() io.Writer {
+	if o.color {
+		return o.w
+	}
+	return o
+}
+
+// Write passes p through, removing escape sequences if color is disabled.
+func (o *Output) Write(p []byte) (int, error) {
+	if o.color {
+		return o.w.Write(p)
+	}
+	if _, err := o.w.Write(o.strip.filter(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Println writes a styled line followed by a newline.
+func (o *Output) Println(line Line) {
+	if o.color {
+		fmt.Fprintln(o.w, line.ANSI())
+	} else {
+		fmt.Fprintln(o.w, line.Plain())
+	}
+}
+
+// stripState removes ANSI escape sequences from a byte stream. It keeps
+// state between calls so sequences split across writes are still removed.
+type stripState int
+
+const (
+	stripText         stripState = iota
+	stripEscape                  // after ESC
+	stripCSI                     // inside ESC [ ... final byte
+	stripString                  // inside OSC, DCS or APC until BEL or ST
+	stripStringEscape            // ESC seen inside a string, expecting '\'
+)
+
+func (s *stripState) filter(p []byte) []byte {
+	out := make([]byte, 0, len(p))
+	for _, b := range p {
+		switch *s {
+		case stripText:
+			if b == 0x1b {
+				*s = stripEscape
+			} else {
+				out = append(out, b)
+			}
+		case stripEscape:
+			switch b {
+			case '[':
+				*s = stripCSI
+			case ']', 'P', '_', '^', 'X':
+				*s = stripString
+			default:
+				*s = stripText
+			}
+		case stripCSI:
+			if b >= 0x40 && b <= 0x7e {
+				*s = stripText
+			}
+		case stripString:
+			if b == 0x07 {
+				*s = stripText
+			} else if b == 0x1b {
+				*s = stripStringEscape
+			}
+		case stripStringEscape:
+			if b == '\\' {
+				*s = stripText
+			} else {
+				*s = stripString
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,37 @@
+package display
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOutputStripsEscapes(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"plain", []string{"hello"}, "hello"},
+		{"csi", []string{"\x1b[1;32mgreen\x1b[0m text"}, "green text"},
+		{"osc ended by bel", []string{"a\x1b]1337;File=inline=1:AAAA\x07b"}, "ab"},
+		{"osc ended by st", []string{"a\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\b"}, "alinkb"},
+		{"apc", []string{"a\x1b_Gf=100;AAAA\x1b\\b"}, "ab"},
+		{"csi split across writes", []string{"a\x1b[38;2;", "255;0;0mred\x1b", "[0m"}, "ared"},
+		{"osc split across writes", []string{"a\x1b]1337;File=", "AAAA\x1b", "\\b"}, "ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			out := &Output{w: &buf}
+			for _, w := range tt.writes {
+				n, err := out.Write([]byte(w))
+				if err != nil || n != len(w) {
+					t.Fatalf("Write(%q) = %d, %v", w, n, err)
+				}
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// paletteSamples caps how many pixels are fed to median cut so that
+	// extraction stays fast regardless of image size.
+	paletteSamples = 4096
+	paletteSize    = 8
+)
+
+// Swatch is one palette entry and the share of sampled pixels it covers.
+type Swatch struct {
+	Color      string  `json:"color"`
+	Population float64 `json:"population"`
+}
+
+// paletteMeta is stored next to a cached image so the palette is only
+// extracted once per file.
+type paletteMeta struct {
+	ModTime int64    `json:"mod_time"`
+	Size    int64    `json:"size"`
+	Palette []Swatch `json:"palette"`
+}
+
+func metaPath(imagePath string) string {
+	return imagePath + ".meta.json"
+}
+
+// ImagePalette returns the dominant colors of the image at path, reading
+// them from the image's metadata file when it is still current.
+func ImagePalette(imagePath string) ([]Swatch, error) {
+	stat, err := os.Stat(imagePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading image: %v", err)
+	}
+
+	if data, err := os.ReadFile(metaPath(imagePath)); err == nil {
+		var meta paletteMeta
+		if json.Unmarshal(data, &meta) == nil && meta.ModTime == stat.ModTime().UnixNano() && meta.Size == stat.Size() && len(meta.Palette) > 0 {
+			return meta.Palette, nil
+		}
+	}
+
+	img, err := loadImage(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	palette := ExtractPalette(img, paletteSize)
+
+	// Caching is best effort; a read-only cache only costs speed
+	meta := paletteMeta{ModTime: stat.ModTime().UnixNano(), Size: stat.Size(), Palette: palette}
+	if data, err := json.Marshal(meta); err == nil {
+		os.WriteFile(metaPath(imagePath), data, 0644)
+	}
+	return palette, nil
+}
+
+// ExtractPalette reduces the image to at most n colors using median cut,
+// ordered by population.
+func ExtractPalette(img image.Image, n int) []Swatch {
+	pixels := samplePixels(img)
+	if len(pixels) == 0 {
+		return nil
+	}
+
+	boxes := [][][3]uint8{pixels}
+	for len(boxes) < n {
+		// Split the box with the widest channel range
+		best, bestRange, bestChannel := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			if channel, r := widestChannel(box); r > bestRange {
+				best, bestRange, bestChannel = i, r, channel
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		box := boxes[best]
+		sort.Slice(box, func(a, b int) bool { return box[a][bestChannel] < box[b][bestChannel] })
+		mid := len(box) / 2
+		boxes[best] = box[:mid]
+		boxes = append(boxes, box[mid:])
+	}
+
+	palette := make([]Swatch, 0, len(boxes))
+	for _, box := range boxes {
+		var sum [3]int
+		for _, p := range box {
+			sum[0] += int(p[0])
+			sum[1] += int(p[1])
+			sum[2] += int(p[2])
+		}
+		c := color.RGBA{uint8(sum[0] / len(box)), uint8(sum[1] / len(box)), uint8(sum[2] / len(box)), 0xff}
+		palette = append(palette, Swatch{
+			Color:      hexColor(c),
+			Population: float64(len(box)) / float64(len(pixels)),
+		})
+	}
+	sort.Slice(palette, func(a, b int) bool { return palette[a].Population > palette[b].Population })
+	return palette
+}
+
+// samplePixels picks up to paletteSamples evenly spaced opaque pixels.
+func samplePixels(img image.Image) [][3]uint8 {
+	b := img.Bounds()
+	step := int(math.Sqrt(float64(b.Dx()*b.Dy()) / paletteSamples))
+	if step < 1 {
+		step = 1
+	}
+
+	pixels := make([][3]uint8, 0, paletteSamples)
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			r, g, bl, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			pixels = append(pixels, [3]uint8{uint8(r >> 8), uint8(g >> 8), uint8(bl >> 8)})
+		}
+	}
+	return pixels
+}
+
+func widestChannel(box [][3]uint8) (int, int) {
+	lo := [3]uint8{255, 255, 255}
+	var hi [3]uint8
+	for _, p := range box {
+		for c := 0; c < 3; c++ {
+			lo[c] = min(lo[c], p[c])
+			hi[c] = max(hi[c], p[c])
+		}
+	}
+
+	channel, width := 0, 0
+	for c := 0; c < 3; c++ {
+		if r := int(hi[c]) - int(lo[c]); r > width {
+			channel, width = c, r
+		}
+	}
+	return channel, width
+}
+
+// DarkBackground guesses whether the terminal background is dark from
+// COLORFGBG ("fg;bg"), as set by rxvt, Konsole and others. Without a hint
+// a dark background is assumed.
+func DarkBackground() bool {
+	parts := strings.Split(os.Getenv("COLORFGBG"), ";")
+	bg, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return true
+	}
+	// Indexes 7 and 9-15 are the light entries of the 16-color palette
+	return !(bg == 7 || (bg >= 9 && bg <= 15))
+}
+
+// AutoTheme derives a theme from the image's palette, adjusting colors
+// until they contrast with the terminal background.
+func AutoTheme(imagePath string, dark bool) (*Theme, error) {
+	palette, err := ImagePalette(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(palette) == 0 {
+		return nil, fmt.Errorf("image has no opaque pixels")
+	}
+
+	background := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	if dark {
+		background = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	}
+
+	// Rank by how colorful each swatch is, favouring common colors
+	colors := make([]color.RGBA, len(palette))
+	scores := make([]float64, len(palette))
+	for i, s := range palette {
+		colors[i], _ = parseHex(s.Color)
+		scores[i] = saturation(colors[i]) * math.Sqrt(s.Population)
+	}
+	order := make([]int, len(palette))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+
+	accent := colors[order[0]]
+	key := accent
+	for _, i := range order[1:] {
+		if hueDistance(colors[i], accent) > 30 {
+			key = colors[i]
+			break
+		}
+	}
+	// Values are read the most, so use the least saturated swatch
+	value := colors[order[len(order)-1]]
+
+	accentHex := hexColor(withContrast(accent, background, 3))
+	keyHex := hexColor(withContrast(key, background, 4.5))
+	valueHex := hexColor(withContrast(value, background, 7))
+
+	return &Theme{
+		Name: "auto",
+		Styles: map[Role]Style{
+			RoleTitle:     {Color: accentHex, Bold: true},
+			RoleSubtitle:  {Color: keyHex, Bold: true},
+			RoleSeparator: {Color: accentHex},
+			RoleKey:       {Color: keyHex, Bold: true},
+			RoleDelimiter: {Color: accentHex},
+			RoleValue:     {Color: valueHex},
+
+			RoleBarOK:       {Color: "green"},
+			RoleBarWarn:     {Color: "yellow"},
+			RoleBarCritical: {Color: "red", Bold: true},
+		},
+	}, nil
+}
+
+// relativeLuminance implements the WCAG 2 definition.
+func relativeLuminance(c color.RGBA) float64 {
+	channel := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+func contrastRatio(a, b color.RGBA) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// withContrast mixes c towards the opposite of background until the WCAG
+// contrast ratio reaches at least ratio.
+func withContrast(c, background color.RGBA, ratio float64) color.RGBA {
+	target := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	if relativeLuminance(background) > 0.5 {
+		target = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	}
+
+	for t := 0.0; t <= 1; t += 0.05 {
+		mixed := color.RGBA{
+			uint8(float64(c.R) + (float64(target.R)-float64(c.R))*t),
+			uint8(float64(c.G) + (float64(target.G)-float64(c.G))*t),
+			uint8(float64(c.B) + (float64(target.B)-float64(c.B))*t),
+			0xff,
+		}
+		if contrastRatio(mixed, background) >= ratio {
+			return mixed
+		}
+	}
+	return target
+}
+
+// hsl returns hue in degrees and saturation and lightness in [0, 1].
+func hsl(c color.RGBA) (float64, float64, float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi, lo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l := (hi + lo) / 2
+	if hi == lo {
+		return 0, 0, l
+	}
+
+	d := hi - lo
+	s := d / (1 - math.Abs(2*l-1))
+	var h float64
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+func saturation(c color.RGBA) float64 {
+	_, s, _ := hsl(c)
+	return s
+}
+
+func hueDistance(a, b color.RGBA) float64 {
+	ha, _, _ := hsl(a)
+	hb, _, _ := hsl(b)
+	d := math.Abs(ha - hb)
+	return math.Min(d, 360-d)
+}
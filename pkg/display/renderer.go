@@ -1,69 +1,211 @@
 package display
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
+	"anifetch/pkg/logo"
 	"anifetch/pkg/system"
 )
 
 type Renderer struct {
-	showImage bool
-	imageSize string
+	showImage  bool
+	imageSize  string
+	backend    string
+	asciiColor bool
+	theme      *Theme
+	bar        Bar
+	logo       *logo.Logo
+	preferLogo bool
+	terminal   string
+	out        *Output
+	errOut     *Output
 }
 
 func NewRenderer(showImage bool) *Renderer {
-	return &Renderer{showImage: showImage, imageSize: "40x20"}
+	r := &Renderer{showImage: showImage, imageSize: "40x20", backend: "auto", asciiColor: true, theme: DefaultTheme()}
+	r.SetColorMode("auto")
+	return r
+}
+
+// SetColorMode applies one of ColorModes to stdout and stderr.
+func (r *Renderer) SetColorMode(mode string) {
+	r.out = NewOutput(os.Stdout, mode)
+	r.errOut = NewOutput(os.Stderr, mode)
+}
+
+func (r *Renderer) SetTheme(theme *Theme) {
+	r.theme = theme
+}
+
+func (r *Renderer) Theme() *Theme {
+	return r.theme
+}
+
+// SetBar enables usage bars, falling back to ASCII characters when the
+// terminal cannot show the configured ones. A zero Width disables them.
+func (r *Renderer) SetBar(bar Bar) {
+	r.bar = asciiBar(bar)
+}
+
+func (r *Renderer) Bar() Bar {
+	return r.bar
+}
+
+// SetLogo sets the ASCII logo shown when no image can be displayed. With
+// prefer set the logo is shown instead of the image.
+func (r *Renderer) SetLogo(l *logo.Logo, prefer bool) {
+	r.logo = l
+	r.preferLogo = prefer
+}
+
+// SetTerminal names the terminal emulator, used to pick an image backend.
+// When unset it is detected the first time the auto backend needs it.
+func (r *Renderer) SetTerminal(name string) {
+	r.terminal = name
 }
 
 func (r *Renderer) SetImageSize(size string) {
 	r.imageSize = size
 }
 
-func (r *Renderer) DisplayInfo(info system.SystemInfo, animeGirlPath string) {
-	// ANSI color codes
-	const (
-		reset   = "\033[0m"
-		bold    = "\033[1m"
-		red     = "\033[31m"
-		green   = "\033[32m"
-		yellow  = "\033[33m"
-		blue    = "\033[34m"
-		magenta = "\033[35m"
-		cyan    = "\033[36m"
-		white   = "\033[37m"
-	)
+func (r *Renderer) SetBackend(backend string) {
+	r.backend = backend
+}
+
+func (r *Renderer) SetASCIIColor(color bool) {
+	r.asciiColor = color
+}
 
+func (r *Renderer) DisplayInfo(report system.Report, animeGirlPath string) {
 	// Display anime girl using various terminal image protocols
-	if r.showImage && animeGirlPath != "" {
+	if r.showImage && !r.preferLogo && animeGirlPath != "" {
 		if !r.displayImage(animeGirlPath) {
-			// Only show ASCII art if no image display methods work
-			r.displayASCIIArt()
+			// Only show the logo if no image display methods work
+			r.displayLogo()
 		}
 	} else if r.showImage {
-		// ASCII art fallback
-		r.displayASCIIArt()
+		// Logo fallback
+		r.displayLogo()
 	}
 
 	// Display system information
-	fmt.Printf("%s%s%s@%s%s %s%s%s\n", bold, green, info.Hostname, reset, bold, blue, info.OS, reset)
-	fmt.Printf("%s%s%s%s %s%s%s\n", bold, green, "────────", reset, bold, blue, "────")
-	fmt.Printf("%sOS:%s %s%s%s\n", bold, reset, yellow, info.OS, reset)
-	fmt.Printf("%sKernel:%s %s%s%s\n", bold, reset, yellow, info.Kernel, reset)
-	fmt.Printf("%sUptime:%s %s%s%s\n", bold, reset, yellow, info.Uptime, reset)
-	fmt.Printf("%sPackages:%s %s%s%s\n", bold, reset, yellow, info.Packages, reset)
-	fmt.Printf("%sShell:%s %s%s%s\n", bold, reset, yellow, info.Shell, reset)
-	fmt.Printf("%sCPU:%s %s%s%s\n", bold, reset, yellow, info.CPU, reset)
-	fmt.Printf("%sMemory:%s %s%s%s\n", bold, reset, yellow, info.Memory, reset)
-	fmt.Printf("%sDisk:%s %s%s%s\n", bold, reset, yellow, info.Disk, reset)
+	for _, line := range InfoLines(report, r.theme, r.bar) {
+		r.out.Println(line)
+	}
+}
+
+// PreviewTheme prints the theme's name followed by sample info lines.
+func (r *Renderer) PreviewTheme(theme *Theme) {
+	sample := system.Report{
+		Hostname: "anifetch",
+		Results: []system.Result{
+			{Name: "os", Label: "OS", Value: system.Value{Text: "Arch Linux x86_64"}},
+			{Name: "kernel", Label: "Kernel", Value: system.Value{Text: "6.9.7"}},
+			{Name: "uptime", Label: "Uptime", Value: system.Value{Text: "3 hours, 5 mins"}},
+			{Name: "packages", Label: "Packages", Value: system.Value{Text: "1432 (pacman)"}},
+			{Name: "shell", Label: "Shell", Value: system.Value{Text: "zsh"}},
+			{Name: "cpu", Label: "CPU", Value: system.Value{Text: "AMD Ryzen 7 5800X"}},
+			{Name: "memory", Label: "Memory", Value: system.Value{Text: "7.64 GiB / 15.52 GiB", Data: system.Percent(49)}},
+			{Name: "disk", Label: "Disk", Value: system.Value{Items: []system.Item{
+				{Name: "/", Text: "112.30 GiB / 467.89 GiB - ext4", Data: system.Percent(24)},
+				{Name: "/data", Text: "1.71 TiB / 1.82 TiB - xfs", Data: system.Percent(94)},
+			}}},
+		},
+	}
+
+	r.out.Println(Line{{theme.Name, Style{Underline: true}}})
+	for _, line := range InfoLines(sample, theme, r.bar) {
+		r.out.Println(line)
+	}
+}
+
+// InfoLines lays out the report as styled lines: a title, a rule and one
+// line per module result, followed by a usage bar where the result is a
+// percentage. Failed and empty modules are left out.
+func InfoLines(report system.Report, theme *Theme, bar Bar) []Line {
+	delimiter := theme.Style(RoleDelimiter)
+	value := theme.Style(RoleValue)
+
+	lines := []Line{
+		{{report.Hostname + "@", theme.Style(RoleTitle)}, {" ", Style{}}, {report.Text("os"), theme.Style(RoleSubtitle)}},
+		{{"──────── ────", theme.Style(RoleSeparator)}},
+	}
+
+	for _, res := range report.Results {
+		text := res.Value.Text
+		style := value
+		if errors.Is(res.Err, system.ErrTimeout) {
+			// Keep the line so a slow module is noticed rather than missing
+			text = "(timed out)"
+			style = delimiter
+		} else if res.Err != nil {
+			continue
+		}
+		if text != "" {
+			lines = append(lines, infoLine(res.Label, text, style, res.Value.Data, bar, theme))
+		}
+		for _, item := range res.Value.Items {
+			lines = append(lines, infoLine(res.Label+" ("+item.Name+")", item.Text, value, item.Data, bar, theme))
+		}
+	}
+	return lines
+}
+
+func infoLine(label, text string, style Style, data any, bar Bar, theme *Theme) Line {
+	line := Line{{label, theme.Style(RoleKey)}, {":", theme.Style(RoleDelimiter)}, {" ", Style{}}, {text, style}}
+	if segments := barSegments(data, bar, theme); segments != nil {
+		line = append(line, Segment{" ", Style{}})
+		line = append(line, segments...)
+	}
+	return line
+}
+
+// DisplayTimings prints how long each module took to collect.
+func (r *Renderer) DisplayTimings(report system.Report) {
+	width := 0
+	for _, res := range report.Results {
+		width = max(width, len(res.Name))
+	}
+
+	key := r.theme.Style(RoleKey)
+	value := r.theme.Style(RoleValue)
+	r.out.Println(Line{{"Timings:", key}})
+	for _, res := range report.Results {
+		status := ""
+		if res.Err != nil {
+			status = " (" + res.Err.Error() + ")"
+		}
+		r.out.Println(Line{
+			{fmt.Sprintf("  %-*s ", width, res.Name), key},
+			{fmt.Sprintf("%8s", res.Duration.Round(time.Microsecond*10)), value},
+			{status, Style{}},
+		})
+	}
+	r.out.Println(Line{{fmt.Sprintf("  %-*s ", width, "total"), key}, {fmt.Sprintf("%8s", report.Duration.Round(time.Microsecond*10)), value}})
 }
 
 func (r *Renderer) displayImage(imagePath string) bool {
 	// Try advanced image display methods with custom size
 	imgDisplay := NewImageDisplayWithSize(r.imageSize)
+	imgDisplay.SetBackend(r.backend)
+	imgDisplay.SetASCIIColor(r.asciiColor)
+	imgDisplay.SetOutput(r.out)
+	if r.terminal == "" && r.backend == "auto" {
+		// Only auto selection cares which terminal this is
+		r.terminal = system.DetectTerminal().Name
+	}
+	imgDisplay.SetTerminal(r.terminal)
 	if imgDisplay.DisplayImage(imagePath) {
 		return true
 	}
+
+	if !r.out.Graphics() {
+		return false
+	}
 	
 	// Fallback to basic terminal protocols
 	term := os.Getenv("TERM")
@@ -71,44 +213,49 @@ func (r *Renderer) displayImage(imagePath string) bool {
 	switch term {
 	case "xterm-kitty":
 		// Kitty terminal image protocol
-		fmt.Printf("\033]1337;File=inline=1;preserveAspectRatio=1:%s\007", imagePath)
+		fmt.Fprintf(r.out, "\033]1337;File=inline=1;preserveAspectRatio=1:%s\007", imagePath)
 		return true
 	case "xterm-256color", "screen-256color":
 		// Try iTerm2 image protocol
-		fmt.Printf("\033]1337;File=inline=1;preserveAspectRatio=1:%s\007", imagePath)
+		fmt.Fprintf(r.out, "\033]1337;File=inline=1;preserveAspectRatio=1:%s\007", imagePath)
 		return true
 	default:
 		// Try generic image protocol
-		fmt.Printf("\033]1337;File=inline=1;preserveAspectRatio=1:%s\007", imagePath)
+		fmt.Fprintf(r.out, "\033]1337;File=inline=1;preserveAspectRatio=1:%s\007", imagePath)
 		return true
 	}
 }
 
-func (r *Renderer) displayASCIIArt() {
-	// Cute ASCII art of an anime girl
-	art := `
-    ╭─────────────────────────╮
-    │      (◕‿◕)             │
-    │      /|\\               │
-    │     / \\                │
-    │                         │
-    │   Holding a Programming │
-    │   Book! 📚              │
-    │                         │
-    │   🎀  Anime Girl  🎀    │
-    ╰─────────────────────────╯
-`
-	fmt.Print(art)
+func (r *Renderer) displayLogo() {
+	if r.logo == nil {
+		return
+	}
+	for _, line := range LogoLines(r.logo) {
+		r.out.Println(line)
+	}
+}
+
+// LogoLines converts a logo's color placeholders into styled lines.
+func LogoLines(l *logo.Logo) []Line {
+	var lines []Line
+	for _, segs := range l.Segments() {
+		line := Line{}
+		for _, seg := range segs {
+			style := Style{Bold: true}
+			if seg.Color >= 0 {
+				style.Color = strconv.Itoa(seg.Color)
+			}
+			line = append(line, Segment{seg.Text, style})
+		}
+		lines = append(lines, line)
+	}
+	return lines
 }
 
 func (r *Renderer) DisplayError(message string) {
-	const red = "\033[31m"
-	const reset = "\033[0m"
-	fmt.Fprintf(os.Stderr, "%sError: %s%s\n", red, message, reset)
+	r.errOut.Println(Line{{"Error: " + message, Style{Color: "red"}}})
 }
 
 func (r *Renderer) DisplaySuccess(message string) {
-	const green = "\033[32m"
-	const reset = "\033[0m"
-	fmt.Printf("%s%s%s\n", green, message, reset)
-} 
\ No newline at end of file
+	r.out.Println(Line{{message, Style{Color: "green"}}})
+}
@@ -0,0 +1,210 @@
+package display
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Style describes how a run of text is drawn. Color is an ANSI color name
+// such as "green" or "bright-blue", a 256-color palette index such as
+// "208", or a "#rrggbb" truecolor value; an empty Color keeps the
+// terminal's default.
+type Style struct {
+	Color     string `json:"color,omitempty"`
+	Bold      bool   `json:"bold,omitempty"`
+	Italic    bool   `json:"italic,omitempty"`
+	Underline bool   `json:"underline,omitempty"`
+}
+
+// Segment is a run of text sharing one style.
+type Segment struct {
+	Text  string
+	Style Style
+}
+
+// Line is one row of styled output.
+type Line []Segment
+
+// ansiCodes maps color names to SGR foreground codes.
+var ansiCodes = map[string]string{
+	"black":   "30",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+}
+
+// brightCodes maps color names to SGR codes of their bright variants.
+var brightCodes = map[string]string{
+	"black":   "90",
+	"red":     "91",
+	"green":   "92",
+	"yellow":  "93",
+	"blue":    "94",
+	"magenta": "95",
+	"cyan":    "96",
+	"white":   "97",
+}
+
+// ansiRGB approximates the default xterm palette for non-terminal output.
+var ansiRGB = map[string]color.RGBA{
+	"black":   {0x00, 0x00, 0x00, 0xff},
+	"red":     {0xcd, 0x00, 0x00, 0xff},
+	"green":   {0x00, 0xcd, 0x00, 0xff},
+	"yellow":  {0xcd, 0xcd, 0x00, 0xff},
+	"blue":    {0x3b, 0x78, 0xff, 0xff},
+	"magenta": {0xcd, 0x00, 0xcd, 0xff},
+	"cyan":    {0x00, 0xcd, 0xcd, 0xff},
+	"white":   {0xe5, 0xe5, 0xe5, 0xff},
+}
+
+// defaultForeground is used when a style has no color.
+var defaultForeground = color.RGBA{0xe5, 0xe5, 0xe5, 0xff}
+
+// parseHex parses a "#rrggbb" color.
+func parseHex(value string) (color.RGBA, error) {
+	if len(value) != 7 || value[0] != '#' {
+		return color.RGBA{}, fmt.Errorf("invalid hex color %q", value)
+	}
+	n, err := strconv.ParseUint(value[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid hex color %q", value)
+	}
+	return color.RGBA{uint8(n >> 16), uint8(n >> 8), uint8(n), 0xff}, nil
+}
+
+// paletteIndex parses a 256-color palette index.
+func paletteIndex(value string) (int, bool) {
+	n, err := strconv.Atoi(value)
+	return n, err == nil && n >= 0 && n <= 255
+}
+
+// paletteRGB approximates a 256-color palette entry.
+func paletteRGB(n int) color.RGBA {
+	names := []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+	switch {
+	case n < 8:
+		return ansiRGB[names[n]]
+	case n < 16:
+		// Bright variants of the first eight
+		c := ansiRGB[names[n-8]]
+		return color.RGBA{max(c.R, 0x7f) | 0x40, max(c.G, 0x7f) | 0x40, max(c.B, 0x7f) | 0x40, 0xff}
+	case n < 232:
+		n -= 16
+		level := func(v int) uint8 {
+			if v == 0 {
+				return 0
+			}
+			return uint8(55 + v*40)
+		}
+		return color.RGBA{level(n / 36), level(n / 6 % 6), level(n % 6), 0xff}
+	default:
+		v := uint8(8 + (n-232)*10)
+		return color.RGBA{v, v, v, 0xff}
+	}
+}
+
+// Validate reports whether the style's color is recognised.
+func (s Style) Validate() error {
+	if _, ok := paletteIndex(s.Color); ok {
+		return nil
+	}
+	switch {
+	case s.Color == "":
+		return nil
+	case strings.HasPrefix(s.Color, "#"):
+		_, err := parseHex(s.Color)
+		return err
+	case strings.HasPrefix(s.Color, "bright-"):
+		if _, ok := brightCodes[strings.TrimPrefix(s.Color, "bright-")]; ok {
+			return nil
+		}
+	default:
+		if _, ok := ansiCodes[s.Color]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown color %q", s.Color)
+}
+
+// ANSI returns the escape sequence that enables the style.
+func (s Style) ANSI() string {
+	var codes []string
+	if s.Bold {
+		codes = append(codes, "1")
+	}
+	if s.Italic {
+		codes = append(codes, "3")
+	}
+	if s.Underline {
+		codes = append(codes, "4")
+	}
+	if code := s.colorCode(); code != "" {
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(codes, ";") + "m"
+}
+
+// colorCode returns the SGR foreground parameters for the style's color.
+func (s Style) colorCode() string {
+	if c, err := parseHex(s.Color); err == nil {
+		return fmt.Sprintf("38;2;%d;%d;%d", c.R, c.G, c.B)
+	}
+	if n, ok := paletteIndex(s.Color); ok {
+		return fmt.Sprintf("38;5;%d", n)
+	}
+	if name, ok := strings.CutPrefix(s.Color, "bright-"); ok {
+		return brightCodes[name]
+	}
+	return ansiCodes[s.Color]
+}
+
+// RGB returns the style's color for image and document output.
+func (s Style) RGB() color.RGBA {
+	if c, err := parseHex(s.Color); err == nil {
+		return c
+	}
+	if n, ok := paletteIndex(s.Color); ok {
+		return paletteRGB(n)
+	}
+	if c, ok := ansiRGB[strings.TrimPrefix(s.Color, "bright-")]; ok {
+		return c
+	}
+	return defaultForeground
+}
+
+// ANSI renders the line with escape sequences, resetting after each styled
+// segment.
+func (l Line) ANSI() string {
+	var sb strings.Builder
+	for _, seg := range l {
+		if seg.Text == "" {
+			continue
+		}
+		if esc := seg.Style.ANSI(); esc != "" {
+			sb.WriteString(esc)
+			sb.WriteString(seg.Text)
+			sb.WriteString("\033[0m")
+		} else {
+			sb.WriteString(seg.Text)
+		}
+	}
+	return sb.String()
+}
+
+// Plain renders the line without any styling.
+func (l Line) Plain() string {
+	var sb strings.Builder
+	for _, seg := range l {
+		sb.WriteString(seg.Text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,255 @@
+package display
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+)
+
+// asciiRamp orders characters from darkest to brightest coverage.
+const asciiRamp = " .'`^\",:;Il!i><~+_-?][}{1)(|/tfjrxnuvczXYUJCLQ0OZmwqpdbkhao*#MW&8%B@$"
+
+// brailleBase is the first code point of the Unicode braille block (U+2800).
+const brailleBase = 0x2800
+
+// brailleDots maps a pixel offset within a 2x4 cell to its braille dot bit.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// loadImage decodes a PNG, JPEG or GIF image from disk.
+func loadImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening image: %v", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %v", err)
+	}
+	return img, nil
+}
+
+// parseSize parses a "COLSxROWS" size string, falling back to 40x20.
+func parseSize(size string) (int, int) {
+	var cols, rows int
+	if _, err := fmt.Sscanf(size, "%dx%d", &cols, &rows); err != nil || cols <= 0 || rows <= 0 {
+		return 40, 20
+	}
+	return cols, rows
+}
+
+// fitCells returns the largest grid of terminal cells not exceeding
+// maxCols x maxRows that keeps the image's aspect ratio. Cells are assumed
+// to be twice as tall as they are wide.
+func fitCells(bounds image.Rectangle, maxCols, maxRows int) (int, int) {
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= 0 || h <= 0 {
+		return 0, 0
+	}
+
+	cols := maxCols
+	rows := cols * h / w / 2
+	if rows > maxRows {
+		rows = maxRows
+		cols = rows * 2 * w / h
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return cols, rows
+}
+
+// pixelGrid is an image resampled to a fixed number of samples by area
+// averaging. Each sample holds 8-bit RGB and a 0-255 luminance.
+type pixelGrid struct {
+	width, height int
+	rgb           [][3]uint8
+	luma          []float64
+}
+
+func resample(img image.Image, width, height int) *pixelGrid {
+	grid := &pixelGrid{
+		width:  width,
+		height: height,
+		rgb:    make([][3]uint8, width*height),
+		luma:   make([]float64, width*height),
+	}
+
+	b := img.Bounds()
+	for gy := 0; gy < height; gy++ {
+		y0 := b.Min.Y + gy*b.Dy()/height
+		y1 := b.Min.Y + (gy+1)*b.Dy()/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for gx := 0; gx < width; gx++ {
+			x0 := b.Min.X + gx*b.Dx()/width
+			x1 := b.Min.X + (gx+1)*b.Dx()/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, bl, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					// RGBA is alpha-premultiplied, so transparent
+					// pixels come out composited onto black
+					pr, pg, pb, _ := img.At(x, y).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					bl += uint64(pb)
+					n++
+				}
+			}
+
+			i := gy*width + gx
+			grid.rgb[i] = [3]uint8{
+				uint8(r / n >> 8),
+				uint8(g / n >> 8),
+				uint8(bl / n >> 8),
+			}
+			grid.luma[i] = luminance(grid.rgb[i])
+		}
+	}
+	return grid
+}
+
+// luminance returns the Rec. 709 relative luminance on a 0-255 scale.
+func luminance(c [3]uint8) float64 {
+	return 0.2126*float64(c[0]) + 0.7152*float64(c[1]) + 0.0722*float64(c[2])
+}
+
+func (g *pixelGrid) meanLuma() float64 {
+	if len(g.luma) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, l := range g.luma {
+		sum += l
+	}
+	return sum / float64(len(g.luma))
+}
+
+// RenderBraille converts an image to Unicode braille characters, packing
+// 2x4 pixels into each cell. A dot is raised where the pixel is brighter
+// than threshold; a negative threshold uses the image's mean luminance.
+func RenderBraille(img image.Image, maxCols, maxRows int, threshold float64) []string {
+	cols, rows := fitCells(img.Bounds(), maxCols, maxRows)
+	if cols == 0 || rows == 0 {
+		return nil
+	}
+
+	grid := resample(img, cols*2, rows*4)
+	if threshold < 0 {
+		threshold = grid.meanLuma()
+	}
+
+	lines := make([]string, rows)
+	for row := 0; row < rows; row++ {
+		var sb strings.Builder
+		for col := 0; col < cols; col++ {
+			cell := rune(brailleBase)
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					if grid.luma[(row*4+dy)*grid.width+col*2+dx] > threshold {
+						cell |= brailleDots[dy][dx]
+					}
+				}
+			}
+			sb.WriteRune(cell)
+		}
+		lines[row] = sb.String()
+	}
+	return lines
+}
+
+// RenderASCII converts an image to characters from a brightness ramp.
+// When color is set every character is tinted with its cell's average
+// color using 256-color escapes.
+func RenderASCII(img image.Image, maxCols, maxRows int, color bool) []string {
+	cols, rows := fitCells(img.Bounds(), maxCols, maxRows)
+	if cols == 0 || rows == 0 {
+		return nil
+	}
+
+	grid := resample(img, cols, rows)
+	last := len(asciiRamp) - 1
+
+	lines := make([]string, rows)
+	for row := 0; row < rows; row++ {
+		var sb strings.Builder
+		prev := -1
+		for col := 0; col < cols; col++ {
+			i := row*cols + col
+			ch := asciiRamp[int(grid.luma[i]/255*float64(last)+0.5)]
+			if color {
+				if code := rgbTo256(grid.rgb[i]); code != prev {
+					fmt.Fprintf(&sb, "\033[38;5;%dm", code)
+					prev = code
+				}
+			}
+			sb.WriteByte(ch)
+		}
+		if color {
+			sb.WriteString("\033[0m")
+		}
+		lines[row] = sb.String()
+	}
+	return lines
+}
+
+// rgbTo256 maps a color to the nearest entry of the xterm 6x6x6 color cube
+// or grayscale ramp.
+func rgbTo256(c [3]uint8) int {
+	r, g, b := int(c[0]), int(c[1]), int(c[2])
+
+	if r == g && g == b {
+		if r < 8 {
+			return 16
+		}
+		if r > 248 {
+			return 231
+		}
+		return 232 + (r-8)*24/241
+	}
+
+	scale := func(v int) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	return 16 + 36*scale(r) + 6*scale(g) + scale(b)
+}
+
+// supportsUnicode reports whether the locale and terminal are likely to
+// render characters outside ASCII, such as braille.
+func supportsUnicode() bool {
+	if os.Getenv("TERM") == "linux" {
+		// The Linux console font has no braille glyphs
+		return false
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return false
+}
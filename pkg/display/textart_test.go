@@ -0,0 +1,142 @@
+package display
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// stripes returns a w x h image whose even columns are a and odd columns b.
+func stripes(w, h int, a, b color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x%2 == 0 {
+				img.Set(x, y, a)
+			} else {
+				img.Set(x, y, b)
+			}
+		}
+	}
+	return img
+}
+
+var (
+	black = color.RGBA{0, 0, 0, 255}
+	white = color.RGBA{255, 255, 255, 255}
+)
+
+func TestFitCells(t *testing.T) {
+	tests := []struct {
+		name             string
+		w, h             int
+		maxCols, maxRows int
+		cols, rows       int
+	}{
+		{"square", 100, 100, 40, 20, 40, 20},
+		{"wide", 200, 100, 40, 20, 40, 10},
+		{"tall", 100, 200, 40, 20, 20, 20},
+		{"sliver", 1000, 1, 40, 20, 40, 1},
+		{"empty", 0, 0, 40, 20, 0, 0},
+	}
+	for _, tt := range tests {
+		cols, rows := fitCells(image.Rect(0, 0, tt.w, tt.h), tt.maxCols, tt.maxRows)
+		if cols != tt.cols || rows != tt.rows {
+			t.Errorf("fitCells(%s) = %dx%d, want %dx%d", tt.name, cols, rows, tt.cols, tt.rows)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in         string
+		cols, rows int
+	}{
+		{"60x30", 60, 30},
+		{"garbage", 40, 20},
+		{"0x10", 40, 20},
+		{"", 40, 20},
+	}
+	for _, tt := range tests {
+		if cols, rows := parseSize(tt.in); cols != tt.cols || rows != tt.rows {
+			t.Errorf("parseSize(%q) = %dx%d, want %dx%d", tt.in, cols, rows, tt.cols, tt.rows)
+		}
+	}
+}
+
+func TestRenderBraille(t *testing.T) {
+	// 8x16 pixels fill 4x4 cells exactly, so each cell samples 2x4 pixels
+	img := stripes(8, 16, white, black)
+	lines := RenderBraille(img, 4, 4, 128)
+	if len(lines) != 4 {
+		t.Fatalf("RenderBraille() gave %d lines, want 4", len(lines))
+	}
+	// Only the left column of dots is raised: dots 1, 2, 3 and 7
+	want := strings.Repeat(string(rune(brailleBase|0x01|0x02|0x04|0x40)), 4)
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) != 4 {
+			t.Errorf("line %d is %d cells wide, want 4", i, utf8.RuneCountInString(line))
+		}
+		if line != want {
+			t.Errorf("line %d = %q, want %q", i, line, want)
+		}
+	}
+
+	// A pixel must be brighter than the threshold to raise its dot
+	gray := color.RGBA{128, 128, 128, 255}
+	for _, line := range RenderBraille(stripes(8, 16, gray, gray), 4, 4, 128) {
+		if line != strings.Repeat("⠀", 4) {
+			t.Errorf("gray at the threshold = %q, want blank cells", line)
+		}
+	}
+
+	// A negative threshold uses the mean, which lies between the stripes
+	if lines := RenderBraille(img, 4, 4, -1); lines[0] != want {
+		t.Errorf("RenderBraille() with the mean threshold = %q, want %q", lines[0], want)
+	}
+
+	if lines := RenderBraille(image.NewRGBA(image.Rect(0, 0, 0, 0)), 4, 4, 128); lines != nil {
+		t.Errorf("RenderBraille() of an empty image = %q, want nil", lines)
+	}
+}
+
+func TestRenderASCII(t *testing.T) {
+	// 4x2 pixels fit 4x1 cells
+	img := stripes(4, 2, black, white)
+	lines := RenderASCII(img, 4, 4, false)
+	if len(lines) != 1 || lines[0] != " $ $" {
+		t.Errorf("RenderASCII() = %q, want [\" $ $\"]", lines)
+	}
+
+	red := color.RGBA{255, 0, 0, 255}
+	lines = RenderASCII(stripes(4, 2, red, red), 4, 4, true)
+	// One escape for the run of equal colors, then a reset
+	want := "\033[38;5;196m<<<<\033[0m"
+	if len(lines) != 1 || lines[0] != want {
+		t.Errorf("RenderASCII() with color = %q, want %q", lines, want)
+	}
+}
+
+func TestRGBTo256(t *testing.T) {
+	tests := []struct {
+		name string
+		c    [3]uint8
+		want int
+	}{
+		{"black", [3]uint8{0, 0, 0}, 16},
+		{"white", [3]uint8{255, 255, 255}, 231},
+		{"red", [3]uint8{255, 0, 0}, 196},
+		{"green", [3]uint8{0, 255, 0}, 46},
+		{"blue", [3]uint8{0, 0, 255}, 21},
+		{"yellow", [3]uint8{255, 255, 0}, 226},
+		{"dark red", [3]uint8{128, 0, 0}, 88},
+		{"gray", [3]uint8{128, 128, 128}, 243},
+	}
+	for _, tt := range tests {
+		if got := rgbTo256(tt.c); got != tt.want {
+			t.Errorf("rgbTo256(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Role names a kind of text in the info block.
+type Role string
+
+const (
+	RoleTitle     Role = "title"     // user@host
+	RoleSubtitle  Role = "subtitle"  // OS next to the title
+	RoleSeparator Role = "separator" // rule under the title
+	RoleKey       Role = "key"       // field labels such as "CPU"
+	RoleDelimiter Role = "delimiter" // ":" between key and value
+	RoleValue     Role = "value"     // field values
+
+	RoleBarOK       Role = "bar-ok"       // usage bars below the warning level
+	RoleBarWarn     Role = "bar-warn"     // usage bars at the warning level
+	RoleBarCritical Role = "bar-critical" // usage bars at the critical level
+)
+
+// Roles lists every role in display order.
+var Roles = []Role{RoleTitle, RoleSubtitle, RoleSeparator, RoleKey, RoleDelimiter, RoleValue, RoleBarOK, RoleBarWarn, RoleBarCritical}
+
+// Theme maps roles to styles. Theme files are JSON documents of the form
+//
+//	{"name": "mine", "styles": {"key": {"color": "#ff79c6", "bold": true}}}
+type Theme struct {
+	Name   string         `json:"name"`
+	Styles map[Role]Style `json:"styles"`
+}
+
+// Style returns the style for role, or no styling if the theme omits it.
+func (t *Theme) Style(role Role) Style {
+	if t == nil {
+		return Style{}
+	}
+	return t.Styles[role]
+}
+
+var builtinThemes = map[string]*Theme{
+	"default": {
+		Name: "default",
+		Styles: map[Role]Style{
+			RoleTitle:       {Color: "green", Bold: true},
+			RoleSubtitle:    {Color: "blue", Bold: true},
+			RoleSeparator:   {Color: "green", Bold: true},
+			RoleKey:         {Bold: true},
+			RoleDelimiter:   {Bold: true},
+			RoleValue:       {Color: "yellow"},
+			RoleBarOK:       {Color: "green"},
+			RoleBarWarn:     {Color: "yellow"},
+			RoleBarCritical: {Color: "red", Bold: true},
+		},
+	},
+	"dracula": {
+		Name: "dracula",
+		Styles: map[Role]Style{
+			RoleTitle:       {Color: "#bd93f9", Bold: true},
+			RoleSubtitle:    {Color: "#ff79c6", Bold: true},
+			RoleSeparator:   {Color: "#6272a4"},
+			RoleKey:         {Color: "#8be9fd", Bold: true},
+			RoleDelimiter:   {Color: "#6272a4"},
+			RoleValue:       {Color: "#f8f8f2"},
+			RoleBarOK:       {Color: "#50fa7b"},
+			RoleBarWarn:     {Color: "#f1fa8c"},
+			RoleBarCritical: {Color: "#ff5555", Bold: true},
+		},
+	},
+	"gruvbox": {
+		Name: "gruvbox",
+		Styles: map[Role]Style{
+			RoleTitle:       {Color: "#fabd2f", Bold: true},
+			RoleSubtitle:    {Color: "#fe8019", Bold: true},
+			RoleSeparator:   {Color: "#928374"},
+			RoleKey:         {Color: "#83a598", Bold: true},
+			RoleDelimiter:   {Color: "#928374"},
+			RoleValue:       {Color: "#ebdbb2"},
+			RoleBarOK:       {Color: "#b8bb26"},
+			RoleBarWarn:     {Color: "#fabd2f"},
+			RoleBarCritical: {Color: "#fb4934", Bold: true},
+		},
+	},
+	"nord": {
+		Name: "nord",
+		Styles: map[Role]Style{
+			RoleTitle:       {Color: "#88c0d0", Bold: true},
+			RoleSubtitle:    {Color: "#81a1c1", Bold: true},
+			RoleSeparator:   {Color: "#4c566a"},
+			RoleKey:         {Color: "#8fbcbb", Bold: true},
+			RoleDelimiter:   {Color: "#4c566a"},
+			RoleValue:       {Color: "#e5e9f0"},
+			RoleBarOK:       {Color: "#a3be8c"},
+			RoleBarWarn:     {Color: "#ebcb8b"},
+			RoleBarCritical: {Color: "#bf616a", Bold: true},
+		},
+	},
+	"monochrome": {
+		Name: "monochrome",
+		Styles: map[Role]Style{
+			RoleTitle:    {Bold: true},
+			RoleSubtitle: {Bold: true},
+			RoleKey:      {Bold: true},
+
+			RoleBarCritical: {Bold: true},
+		},
+	},
+}
+
+// DefaultTheme returns the built-in default theme.
+func DefaultTheme() *Theme {
+	return builtinThemes["default"]
+}
+
+// ThemeNames lists built-in themes followed by *.json themes in dir.
+func ThemeNames(dir string) []string {
+	var names []string
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		if _, ok := builtinThemes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// LoadTheme resolves name as a built-in theme, a theme file in dir, or a
+// path to a theme file, in that order.
+func LoadTheme(name, dir string) (*Theme, error) {
+	if theme, ok := builtinThemes[name]; ok {
+		return theme, nil
+	}
+
+	path := filepath.Join(dir, name+".json")
+	if _, err := os.Stat(path); err != nil {
+		path = name
+	}
+	return LoadThemeFile(path)
+}
+
+// LoadThemeFile reads and validates a JSON theme file.
+func LoadThemeFile(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading theme: %v", err)
+	}
+
+	var theme Theme
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return nil, fmt.Errorf("error parsing theme %s: %v", path, err)
+	}
+	for role, style := range theme.Styles {
+		if !knownRole(role) {
+			return nil, fmt.Errorf("theme %s: unknown role %q", path, role)
+		}
+		if err := style.Validate(); err != nil {
+			return nil, fmt.Errorf("theme %s, role %s: %v", path, role, err)
+		}
+	}
+	if theme.Name == "" {
+		theme.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return &theme, nil
+}
+
+func knownRole(role Role) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+// Package logo provides the colored ASCII distribution logos shown when no
+// image is displayed. Logos use neofetch's syntax: ${c1} to ${c6} switch to
+// the logo's first to sixth color.
+package logo
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//go:embed logos/*.txt
+var files embed.FS
+
+// Fallback is the logo used when no distribution matches.
+const Fallback = "linux"
+
+// Segment is a run of logo text drawn in one color. Color is a 256-color
+// palette index, or -1 for the terminal's default color.
+type Segment struct {
+	Text  string
+	Color int
+}
+
+// Logo is a multi-line ASCII logo with up to six colors.
+type Logo struct {
+	Name   string
+	Lines  []string
+	Colors []int
+}
+
+var placeholder = regexp.MustCompile(`\$\{c([1-6])\}`)
+
+// Parse reads a logo. An optional first line "# colors: 4 6" lists the
+// palette indexes that ${c1}, ${c2} and so on refer to.
+func Parse(name string, data []byte) (*Logo, error) {
+	text := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	lines := strings.Split(text, "\n")
+	l := &Logo{Name: name}
+
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "# colors:") {
+		for _, field := range strings.Fields(strings.TrimPrefix(lines[0], "# colors:")) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 0 || n > 255 {
+				return nil, fmt.Errorf("logo %s: invalid color %q", name, field)
+			}
+			l.Colors = append(l.Colors, n)
+		}
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("logo %s is empty", name)
+	}
+	l.Lines = lines
+	return l, nil
+}
+
+// Load reads a user-supplied logo file.
+func Load(path string) (*Logo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading logo: %v", err)
+	}
+	return Parse(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), data)
+}
+
+// Get returns the built-in logo called name.
+func Get(name string) (*Logo, bool) {
+	data, err := files.ReadFile("logos/" + name + ".txt")
+	if err != nil {
+		return nil, false
+	}
+	l, err := Parse(name, data)
+	if err != nil {
+		return nil, false
+	}
+	return l, true
+}
+
+// ForDistro returns the logo of the first of ids that has one, such as an
+// os-release ID followed by its ID_LIKE entries, or the generic logo.
+func ForDistro(ids []string) *Logo {
+	for _, id := range ids {
+		if l, ok := Get(id); ok {
+			return l
+		}
+	}
+	l, _ := Get(Fallback)
+	return l
+}
+
+// Segments splits each line at color placeholders. The color in effect
+// carries over to following lines, as in neofetch.
+func (l *Logo) Segments() [][]Segment {
+	current := -1
+	if len(l.Colors) > 0 {
+		current = l.Colors[0]
+	}
+
+	out := make([][]Segment, len(l.Lines))
+	for i, line := range l.Lines {
+		var segs []Segment
+		last := 0
+		for _, m := range placeholder.FindAllStringSubmatchIndex(line, -1) {
+			if m[0] > last {
+				segs = append(segs, Segment{line[last:m[0]], current})
+			}
+			n, _ := strconv.Atoi(line[m[2]:m[3]])
+			current = -1
+			if n <= len(l.Colors) {
+				current = l.Colors[n-1]
+			}
+			last = m[1]
+		}
+		if last < len(line) {
+			segs = append(segs, Segment{line[last:], current})
+		}
+		out[i] = segs
+	}
+	return out
+}
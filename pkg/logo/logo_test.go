@@ -0,0 +1,125 @@
+package logo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	l, err := Parse("test", []byte("# colors: 4 6\r\n${c1}ab\r\n${c2}cd\r\n\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Logo{Name: "test", Lines: []string{"${c1}ab", "${c2}cd"}, Colors: []int{4, 6}}
+	if !reflect.DeepEqual(l, want) {
+		t.Errorf("Parse() = %+v, want %+v", l, want)
+	}
+
+	l, err = Parse("plain", []byte("  /\\\n /  \\\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Colors) != 0 || len(l.Lines) != 2 {
+		t.Errorf("Parse() without colors = %+v", l)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct{ name, data string }{
+		{"bad color", "# colors: 4 blue\nx\n"},
+		{"color out of range", "# colors: 256\nx\n"},
+		{"only colors", "# colors: 1 2\n"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.name, []byte(tt.data)); err == nil {
+			t.Errorf("Parse(%s) succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	tests := []struct {
+		name   string
+		colors []int
+		lines  []string
+		want   [][]Segment
+	}{
+		{"switch mid-line", []int{1, 2}, []string{"${c1}ab${c2}cd"}, [][]Segment{
+			{{"ab", 1}, {"cd", 2}},
+		}},
+		{"first color before any placeholder", []int{5}, []string{"ab${c1}cd"}, [][]Segment{
+			{{"ab", 5}, {"cd", 5}},
+		}},
+		{"color carries over lines", []int{1, 2}, []string{"${c2}ab", "cd", "${c1}ef"}, [][]Segment{
+			{{"ab", 2}},
+			{{"cd", 2}},
+			{{"ef", 1}},
+		}},
+		{"no colors", nil, []string{"${c1}ab"}, [][]Segment{
+			{{"ab", -1}},
+		}},
+		{"index beyond the colors", []int{1, 2}, []string{"${c1}ab${c3}cd"}, [][]Segment{
+			{{"ab", 1}, {"cd", -1}},
+		}},
+		{"c0 and c7 are literal", []int{1}, []string{"a${c0}b${c7}"}, [][]Segment{
+			{{"a${c0}b${c7}", 1}},
+		}},
+		{"literal dollar", []int{3}, []string{"$ ${x} $c1 ${c"}, [][]Segment{
+			{{"$ ${x} $c1 ${c", 3}},
+		}},
+		{"empty line", []int{1}, []string{"${c1}ab", ""}, [][]Segment{
+			{{"ab", 1}},
+			nil,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Logo{Lines: tt.lines, Colors: tt.colors}
+			if got := l.Segments(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Segments() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinLogos(t *testing.T) {
+	entries, err := files.ReadDir("logos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".txt")
+		if _, ok := Get(name); !ok {
+			t.Errorf("built-in logo %s does not parse", name)
+		}
+	}
+}
+
+func TestForDistro(t *testing.T) {
+	if l := ForDistro([]string{"pop", "ubuntu", "debian"}); l.Name != "ubuntu" {
+		t.Errorf("ForDistro() = %s, want ubuntu", l.Name)
+	}
+	if l := ForDistro([]string{"unknownos"}); l.Name != Fallback {
+		t.Errorf("ForDistro() = %s, want %s", l.Name, Fallback)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mine.txt")
+	if err := os.WriteFile(path, []byte("# colors: 2\n${c1}hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	l, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Name != "mine" || !reflect.DeepEqual(l.Colors, []int{2}) {
+		t.Errorf("Load() = %+v", l)
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected an error for a missing logo")
+	}
+}
@@ -0,0 +1,138 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+)
+
+func init() {
+	Register(NewModule("battery", "Battery", func(ctx context.Context) (Value, error) {
+		batteries := readPowerSupplies(sysRoot)
+		switch len(batteries) {
+		case 0:
+			// A desktop, or a laptop without a battery fitted
+			return Value{}, nil
+		case 1:
+			return Value{Text: batteries[0].String(), Data: batteries[0]}, nil
+		}
+
+		var value Value
+		for _, b := range batteries {
+			value.Items = append(value.Items, Item{Name: b.Name, Text: b.String(), Data: b})
+		}
+		return value, nil
+	}))
+}
+
+// Battery is the state of one battery from the power_supply class.
+type Battery struct {
+	Name     string  `json:"name"`
+	Capacity float64 `json:"capacity"` // percent charged
+	Status   string  `json:"status"`   // Charging, Discharging, Full, ...
+	// RemainingMinutes is the time to empty while discharging or to full
+	// while charging; zero when the battery does not report its power draw
+	RemainingMinutes int `json:"remaining_minutes"`
+	// AC is whether mains power is connected
+	AC bool `json:"ac"`
+}
+
+func (b Battery) Percentage() float64 { return b.Capacity }
+
+// String formats the battery like "87% [Discharging, 3h12m]".
+func (b Battery) String() string {
+	s := fmt.Sprintf("%.0f%%", b.Capacity)
+	status := b.Status
+	if status == "" && b.AC {
+		status = "AC connected"
+	}
+	if b.RemainingMinutes > 0 {
+		status += fmt.Sprintf(", %dh%02dm", b.RemainingMinutes/60, b.RemainingMinutes%60)
+	}
+	if status != "" {
+		s += " [" + status + "]"
+	}
+	return s
+}
+
+// readPowerSupplies reads the system batteries from
+// <root>/class/power_supply, noting on each whether a mains adapter is
+// online. Batteries of peripherals such as mice are skipped.
+func readPowerSupplies(root string) []Battery {
+	dirs, _ := filepath.Glob(filepath.Join(root, "class/power_supply/*"))
+	var batteries []Battery
+	ac := false
+	for _, dir := range dirs {
+		switch readSysfsString(filepath.Join(dir, "type")) {
+		case "Mains", "USB":
+			if readSysfsString(filepath.Join(dir, "online")) == "1" {
+				ac = true
+			}
+		case "Battery":
+			if readSysfsString(filepath.Join(dir, "scope")) == "Device" {
+				continue
+			}
+			if readSysfsString(filepath.Join(dir, "present")) == "0" {
+				continue
+			}
+			if b, ok := readBattery(dir); ok {
+				batteries = append(batteries, b)
+			}
+		}
+	}
+	for i := range batteries {
+		batteries[i].AC = ac
+	}
+	return batteries
+}
+
+// readBattery reads one battery directory. Batteries report either energy
+// (µWh, µW) or charge (µAh, µA); the arithmetic is the same for both.
+func readBattery(dir string) (Battery, bool) {
+	b := Battery{Name: filepath.Base(dir), Status: readSysfsString(filepath.Join(dir, "status"))}
+	if b.Status == "Unknown" {
+		b.Status = ""
+	}
+
+	now, full, rate := sysfsInt(dir, "energy_now"), sysfsInt(dir, "energy_full"), sysfsInt(dir, "power_now")
+	if now < 0 || full <= 0 {
+		now, full, rate = sysfsInt(dir, "charge_now"), sysfsInt(dir, "charge_full"), sysfsInt(dir, "current_now")
+	}
+
+	if capacity := sysfsInt(dir, "capacity"); capacity >= 0 {
+		b.Capacity = float64(capacity)
+	} else if now >= 0 && full > 0 {
+		b.Capacity = 100 * float64(now) / float64(full)
+	} else {
+		return Battery{}, false
+	}
+
+	if rate > 0 && now >= 0 {
+		var hours float64
+		switch b.Status {
+		case "Discharging":
+			hours = float64(now) / float64(rate)
+		case "Charging":
+			if full > now {
+				hours = float64(full-now) / float64(rate)
+			}
+		}
+		b.RemainingMinutes = int(math.Round(hours * 60))
+	}
+	return b, true
+}
+
+// sysfsInt reads an integer attribute, returning -1 if it is missing.
+func sysfsInt(dir, name string) int64 {
+	n, err := strconv.ParseInt(readSysfsString(filepath.Join(dir, name)), 10, 64)
+	if err != nil {
+		return -1
+	}
+	// Some drivers report the current as negative while discharging
+	if n < 0 {
+		n = -n
+	}
+	return n
+}
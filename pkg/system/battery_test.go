@@ -0,0 +1,122 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadPowerSupplies(t *testing.T) {
+	energy := map[string]string{
+		"BAT0/type":        "Battery\n",
+		"BAT0/status":      "Discharging\n",
+		"BAT0/present":     "1\n",
+		"BAT0/capacity":    "80\n",
+		"BAT0/energy_now":  "40000000\n",
+		"BAT0/energy_full": "50000000\n",
+		"BAT0/power_now":   "10000000\n",
+	}
+	// No capacity file, and the current is negative while charging
+	charge := map[string]string{
+		"BAT1/type":        "Battery\n",
+		"BAT1/status":      "Charging\n",
+		"BAT1/charge_now":  "2000000\n",
+		"BAT1/charge_full": "4000000\n",
+		"BAT1/current_now": "-1000000\n",
+	}
+
+	tests := []struct {
+		name  string
+		files []map[string]string
+		want  []Battery
+	}{
+		{"energy", []map[string]string{energy}, []Battery{
+			{Name: "BAT0", Capacity: 80, Status: "Discharging", RemainingMinutes: 240},
+		}},
+		{"charge", []map[string]string{charge}, []Battery{
+			{Name: "BAT1", Capacity: 50, Status: "Charging", RemainingMinutes: 120},
+		}},
+		{"capacity only", []map[string]string{{
+			"BAT0/type":     "Battery\n",
+			"BAT0/status":   "Full\n",
+			"BAT0/capacity": "100\n",
+		}}, []Battery{
+			{Name: "BAT0", Capacity: 100, Status: "Full"},
+		}},
+		{"no readings", []map[string]string{{
+			"BAT0/type":   "Battery\n",
+			"BAT0/status": "Unknown\n",
+		}}, nil},
+		{"not present", []map[string]string{{
+			"BAT0/type":     "Battery\n",
+			"BAT0/present":  "0\n",
+			"BAT0/capacity": "0\n",
+		}}, nil},
+		{"peripheral", []map[string]string{{
+			"hidpp_battery_0/type":     "Battery\n",
+			"hidpp_battery_0/scope":    "Device\n",
+			"hidpp_battery_0/capacity": "55\n",
+		}}, nil},
+		{"mains online", []map[string]string{{
+			"AC/type":       "Mains\n",
+			"AC/online":     "1\n",
+			"BAT0/type":     "Battery\n",
+			"BAT0/status":   "Unknown\n",
+			"BAT0/capacity": "87\n",
+		}}, []Battery{
+			{Name: "BAT0", Capacity: 87, AC: true},
+		}},
+		{"mains offline", []map[string]string{energy, {
+			"AC/type":   "Mains\n",
+			"AC/online": "0\n",
+		}}, []Battery{
+			{Name: "BAT0", Capacity: 80, Status: "Discharging", RemainingMinutes: 240},
+		}},
+		{"two batteries", []map[string]string{energy, charge}, []Battery{
+			{Name: "BAT0", Capacity: 80, Status: "Discharging", RemainingMinutes: 240},
+			{Name: "BAT1", Capacity: 50, Status: "Charging", RemainingMinutes: 120},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, files := range tt.files {
+				writeFiles(t, filepath.Join(root, "class/power_supply"), files)
+			}
+			if got := readPowerSupplies(root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readPowerSupplies() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPowerSuppliesEmpty(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "class/power_supply"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := readPowerSupplies(root); len(got) != 0 {
+		t.Errorf("readPowerSupplies() = %+v, want nothing", got)
+	}
+	if got := readPowerSupplies(t.TempDir()); len(got) != 0 {
+		t.Errorf("readPowerSupplies() without power_supply = %+v, want nothing", got)
+	}
+}
+
+func TestBatteryString(t *testing.T) {
+	tests := []struct {
+		b    Battery
+		want string
+	}{
+		{Battery{Capacity: 87, Status: "Discharging", RemainingMinutes: 192}, "87% [Discharging, 3h12m]"},
+		{Battery{Capacity: 100, Status: "Full", AC: true}, "100% [Full]"},
+		{Battery{Capacity: 87, AC: true}, "87% [AC connected]"},
+		{Battery{Capacity: 42.4}, "42%"},
+	}
+	for _, tt := range tests {
+		if got := tt.b.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(NewModule("cpu", "CPU", func(ctx context.Context) (Value, error) {
+		cpu, err := detectCPU()
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{Text: cpu.String(), Data: cpu}, nil
+	}))
+	Register(NewModule("cpuusage", "CPU Usage", func(ctx context.Context) (Value, error) {
+		usage, err := cpuUsage(ctx, procRoot, cpuUsageInterval)
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{Text: fmt.Sprintf("%.0f%%", usage), Data: Percent(usage)}, nil
+	}))
+}
+
+// CPU describes the processor.
+type CPU struct {
+	Model   string  `json:"model"`
+	Cores   int     `json:"cores"`   // physical cores
+	Threads int     `json:"threads"` // logical processors
+	MaxGHz  float64 `json:"max_ghz"` // zero when unknown
+}
+
+// String formats the CPU like "AMD Ryzen 7 5800X (16) @ 4.85 GHz".
+func (c CPU) String() string {
+	s := c.Model
+	if s == "" {
+		s = "Unknown CPU"
+	}
+	if c.Threads > 0 {
+		s += fmt.Sprintf(" (%d)", c.Threads)
+	}
+	if c.MaxGHz > 0 {
+		s += fmt.Sprintf(" @ %.2f GHz", c.MaxGHz)
+	}
+	return s
+}
+
+// readCPUInfo builds a CPU from procfs and sysfs rooted at procRoot and
+// sysRoot. Model names come from "model name", then the ARM "Hardware"
+// field, then the implementer/part tables, then the device tree.
+func readCPUInfo(procRoot, sysRoot string) (CPU, error) {
+	data, err := os.ReadFile(filepath.Join(procRoot, "cpuinfo"))
+	if err != nil {
+		return CPU{}, fmt.Errorf("error reading cpuinfo: %v", err)
+	}
+
+	var cpu CPU
+	var hardware, implementer string
+	var parts []string
+	var mhz float64
+	cores := map[string]bool{}
+	physicalID := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "processor":
+			cpu.Threads++
+		case "model name":
+			if cpu.Model == "" {
+				cpu.Model = value
+			}
+		case "Hardware":
+			hardware = value
+		case "CPU implementer":
+			if implementer == "" {
+				implementer = value
+			}
+		case "CPU part":
+			// big.LITTLE systems list a part per cluster
+			if !slices.Contains(parts, value) {
+				parts = append(parts, value)
+			}
+		case "cpu MHz":
+			if f, err := strconv.ParseFloat(value, 64); err == nil && f > mhz {
+				mhz = f
+			}
+		case "physical id":
+			physicalID = value
+		case "core id":
+			cores[physicalID+"/"+value] = true
+		}
+	}
+
+	switch {
+	case cpu.Model != "":
+	case hardware != "":
+		cpu.Model = hardware
+	case armCPUName(implementer, parts) != "":
+		cpu.Model = armCPUName(implementer, parts)
+	default:
+		cpu.Model = deviceTreeSoC(procRoot, sysRoot)
+	}
+	cpu.Model = cleanCPUModel(cpu.Model)
+
+	cpu.Cores = len(cores)
+	if cpu.Cores == 0 {
+		cpu.Cores = sysfsCores(sysRoot)
+	}
+	if khz := maxCPUFreq(sysRoot); khz > 0 {
+		cpu.MaxGHz = float64(khz) / 1e6
+	} else {
+		// Without cpufreq the current clock is the best there is
+		cpu.MaxGHz = mhz / 1000
+	}
+	return cpu, nil
+}
+
+// armImplementers maps "CPU implementer" codes to vendor names.
+var armImplementers = map[string]string{
+	"0x41": "ARM",
+	"0x42": "Broadcom",
+	"0x43": "Cavium",
+	"0x46": "Fujitsu",
+	"0x48": "HiSilicon",
+	"0x4e": "NVIDIA",
+	"0x50": "APM",
+	"0x51": "Qualcomm",
+	"0x61": "Apple",
+	"0xc0": "Ampere",
+}
+
+// armParts maps implementer and "CPU part" codes to core names.
+var armParts = map[string]map[string]string{
+	"0x41": {
+		"0xb76": "ARM1176",
+		"0xc07": "Cortex-A7",
+		"0xc08": "Cortex-A8",
+		"0xc09": "Cortex-A9",
+		"0xc0f": "Cortex-A15",
+		"0xd03": "Cortex-A53",
+		"0xd04": "Cortex-A35",
+		"0xd05": "Cortex-A55",
+		"0xd07": "Cortex-A57",
+		"0xd08": "Cortex-A72",
+		"0xd09": "Cortex-A73",
+		"0xd0a": "Cortex-A75",
+		"0xd0b": "Cortex-A76",
+		"0xd0c": "Neoverse-N1",
+		"0xd0d": "Cortex-A77",
+		"0xd40": "Neoverse-V1",
+		"0xd41": "Cortex-A78",
+		"0xd44": "Cortex-X1",
+		"0xd46": "Cortex-A510",
+		"0xd47": "Cortex-A710",
+		"0xd48": "Cortex-X2",
+		"0xd49": "Neoverse-N2",
+		"0xd4f": "Neoverse-V2",
+		"0xd80": "Cortex-A520",
+		"0xd81": "Cortex-A720",
+	},
+	"0x51": {
+		"0x800": "Kryo 2XX Gold",
+		"0x801": "Kryo 2XX Silver",
+		"0x802": "Kryo 4XX Gold",
+		"0x803": "Kryo 4XX Silver",
+		"0x805": "Kryo 4XX Silver",
+	},
+	"0xc0": {
+		"0xac3": "Ampere-1",
+		"0xac4": "Ampere-1a",
+	},
+}
+
+// armCPUName names the ARM cores, e.g. "ARM Cortex-A72", or
+// "ARM Cortex-A55 + Cortex-A76" for a big.LITTLE design.
+func armCPUName(implementer string, parts []string) string {
+	implementer = strings.ToLower(implementer)
+	vendor, ok := armImplementers[implementer]
+	if !ok {
+		return ""
+	}
+	var names []string
+	for _, part := range parts {
+		if name, ok := armParts[implementer][strings.ToLower(part)]; ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return vendor
+	}
+	return vendor + " " + strings.Join(names, " + ")
+}
+
+// deviceTreeSoC names the SoC from the last device-tree compatible entry,
+// e.g. "brcm,bcm2711" becomes "Broadcom BCM2711".
+func deviceTreeSoC(procRoot, sysRoot string) string {
+	var data []byte
+	for _, path := range []string{
+		filepath.Join(sysRoot, "firmware/devicetree/base/compatible"),
+		filepath.Join(procRoot, "device-tree/compatible"),
+	} {
+		var err error
+		if data, err = os.ReadFile(path); err == nil {
+			break
+		}
+	}
+	entries := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+	last := entries[len(entries)-1]
+	vendor, model, ok := strings.Cut(last, ",")
+	if !ok {
+		return ""
+	}
+	if name, ok := socVendors[vendor]; ok {
+		vendor = name
+	}
+	return vendor + " " + strings.ToUpper(model)
+}
+
+// socVendors maps device-tree vendor prefixes to display names.
+var socVendors = map[string]string{
+	"allwinner": "Allwinner",
+	"amlogic":   "Amlogic",
+	"brcm":      "Broadcom",
+	"fsl":       "NXP",
+	"mediatek":  "MediaTek",
+	"nvidia":    "NVIDIA",
+	"qcom":      "Qualcomm",
+	"rockchip":  "Rockchip",
+	"samsung":   "Samsung",
+	"ti":        "TI",
+}
+
+var (
+	cpuModelNoise = regexp.MustCompile(`\((R|r|TM|tm)\)|\s+CPU|\s+@\s+[0-9.]+\s*[GM]Hz|\s+[0-9]+-Core Processor|\s+Processor$`)
+	spaces        = regexp.MustCompile(`\s+`)
+)
+
+// cleanCPUModel drops trademarks and clock suffixes that the frequency
+// field already covers.
+func cleanCPUModel(model string) string {
+	model = cpuModelNoise.ReplaceAllString(model, "")
+	return strings.TrimSpace(spaces.ReplaceAllString(model, " "))
+}
+
+// sysfsCores counts distinct package/core pairs, for platforms whose
+// cpuinfo has no core ids.
+func sysfsCores(root string) int {
+	dirs, _ := filepath.Glob(filepath.Join(root, "devices/system/cpu/cpu[0-9]*/topology"))
+	cores := map[string]bool{}
+	for _, dir := range dirs {
+		pkg := readSysfsString(filepath.Join(dir, "physical_package_id"))
+		core := readSysfsString(filepath.Join(dir, "core_id"))
+		if core != "" {
+			cores[pkg+"/"+core] = true
+		}
+	}
+	return len(cores)
+}
+
+// maxCPUFreq returns the highest cpuinfo_max_freq in kHz of any CPU.
+func maxCPUFreq(root string) int {
+	files, _ := filepath.Glob(filepath.Join(root, "devices/system/cpu/cpu[0-9]*/cpufreq/cpuinfo_max_freq"))
+	highest := 0
+	for _, file := range files {
+		if khz, err := strconv.Atoi(readSysfsString(file)); err == nil && khz > highest {
+			highest = khz
+		}
+	}
+	return highest
+}
+
+// cpuUsageInterval is how long cpuUsage samples /proc/stat for.
+const cpuUsageInterval = 200 * time.Millisecond
+
+// cpuUsage samples the aggregate line of <root>/stat twice and returns the
+// busy percentage in between.
+func cpuUsage(ctx context.Context, root string, interval time.Duration) (float64, error) {
+	idle1, total1, err := readCPUTimes(root)
+	if err != nil {
+		return 0, err
+	}
+	select {
+	case <-time.After(interval):
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	idle2, total2, err := readCPUTimes(root)
+	if err != nil {
+		return 0, err
+	}
+	return busyPercent(idle1, total1, idle2, total2), nil
+}
+
+// busyPercent is the share of non-idle jiffies between two samples.
+func busyPercent(idle1, total1, idle2, total2 uint64) float64 {
+	if total2 <= total1 {
+		return 0
+	}
+	return 100 * (1 - float64(idle2-idle1)/float64(total2-total1))
+}
+
+// readCPUTimes returns the idle and total jiffies from the "cpu" line.
+func readCPUTimes(root string) (idle, total uint64, err error) {
+	data, err := os.ReadFile(filepath.Join(root, "stat"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("error reading stat: %v", err)
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("unexpected %s/stat format", root)
+	}
+	for i, field := range fields[1:] {
+		n, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("error parsing %s/stat: %v", root, err)
+		}
+		// guest and guest_nice are already counted in user and nice
+		if i >= 8 {
+			break
+		}
+		total += n
+		// idle and iowait
+		if i == 3 || i == 4 {
+			idle += n
+		}
+	}
+	return idle, total, nil
+}
@@ -0,0 +1,31 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package system
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// detectCPU reads the processor from sysctl. Apple Silicon and the BSDs
+// name the model differently, so several keys are tried.
+func detectCPU() (CPU, error) {
+	var cpu CPU
+	for _, key := range []string{"machdep.cpu.brand_string", "hw.model"} {
+		if model, err := unix.Sysctl(key); err == nil && model != "" {
+			cpu.Model = cleanCPUModel(model)
+			break
+		}
+	}
+	if n, err := unix.SysctlUint32("hw.ncpu"); err == nil {
+		cpu.Threads = int(n)
+	}
+	if n, err := unix.SysctlUint32("hw.physicalcpu"); err == nil {
+		cpu.Cores = int(n)
+	}
+	if hz, err := unix.SysctlUint64("hw.cpufrequency_max"); err == nil {
+		cpu.MaxGHz = float64(hz) / 1e9
+	} else if mhz, err := unix.SysctlUint32("hw.clockrate"); err == nil {
+		cpu.MaxGHz = float64(mhz) / 1000
+	}
+	return cpu, nil
+}
@@ -0,0 +1,5 @@
+package system
+
+func detectCPU() (CPU, error) {
+	return readCPUInfo(procRoot, sysRoot)
+}
@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package system
+
+import "runtime"
+
+func detectCPU() (CPU, error) {
+	return CPU{Threads: runtime.NumCPU()}, nil
+}
@@ -0,0 +1,176 @@
+package system
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const x86CPUInfo = `processor	: 0
+vendor_id	: AuthenticAMD
+model name	: AMD Ryzen 7 5800X 8-Core Processor
+physical id	: 0
+core id		: 0
+cpu MHz		: 3400.000
+
+processor	: 1
+vendor_id	: AuthenticAMD
+model name	: AMD Ryzen 7 5800X 8-Core Processor
+physical id	: 0
+core id		: 0
+cpu MHz		: 4200.000
+
+processor	: 2
+vendor_id	: AuthenticAMD
+model name	: AMD Ryzen 7 5800X 8-Core Processor
+physical id	: 0
+core id		: 1
+cpu MHz		: 2200.000
+`
+
+// armCPUInfo returns an aarch64 cpuinfo with one processor per part.
+func armCPUInfo(implementer string, parts ...string) string {
+	s := ""
+	for i, part := range parts {
+		s += "processor\t: " + strconv.Itoa(i) + "\nBogoMIPS\t: 108.00\n" +
+			"CPU implementer\t: " + implementer + "\nCPU part\t: " + part + "\n\n"
+	}
+	return s
+}
+
+func TestReadCPUInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		proc map[string]string
+		sys  map[string]string
+		want CPU
+	}{
+		{"x86 with cpufreq", map[string]string{"cpuinfo": x86CPUInfo}, map[string]string{
+			"devices/system/cpu/cpu0/cpufreq/cpuinfo_max_freq": "4850000\n",
+			"devices/system/cpu/cpu1/cpufreq/cpuinfo_max_freq": "4700000\n",
+		}, CPU{Model: "AMD Ryzen 7 5800X", Cores: 2, Threads: 3, MaxGHz: 4.85}},
+		{"x86 without cpufreq", map[string]string{"cpuinfo": x86CPUInfo}, nil,
+			CPU{Model: "AMD Ryzen 7 5800X", Cores: 2, Threads: 3, MaxGHz: 4.2}},
+		{"ARM part", map[string]string{"cpuinfo": armCPUInfo("0x41", "0xd08", "0xd08")}, map[string]string{
+			"devices/system/cpu/cpu0/topology/physical_package_id": "0\n",
+			"devices/system/cpu/cpu0/topology/core_id":             "0\n",
+			"devices/system/cpu/cpu1/topology/physical_package_id": "0\n",
+			"devices/system/cpu/cpu1/topology/core_id":             "1\n",
+			"devices/system/cpu/cpu0/cpufreq/cpuinfo_max_freq":     "1800000\n",
+		}, CPU{Model: "ARM Cortex-A72", Cores: 2, Threads: 2, MaxGHz: 1.8}},
+		{"ARM big.LITTLE", map[string]string{"cpuinfo": armCPUInfo("0x41", "0xd05", "0xd05", "0xd0b", "0xd0b")}, nil,
+			CPU{Model: "ARM Cortex-A55 + Cortex-A76", Threads: 4}},
+		{"ARM unknown part", map[string]string{"cpuinfo": armCPUInfo("0x41", "0xfff")}, nil,
+			CPU{Model: "ARM", Threads: 1}},
+		{"ARM Hardware", map[string]string{
+			"cpuinfo": armCPUInfo("0x41", "0xb76") + "Hardware\t: BCM2835\nRevision\t: 9000c1\n",
+		}, nil, CPU{Model: "BCM2835", Threads: 1}},
+		{"device tree in sysfs", map[string]string{"cpuinfo": armCPUInfo("0x99", "0x001")}, map[string]string{
+			"firmware/devicetree/base/compatible": "raspberrypi,4-model-b\x00brcm,bcm2711\x00",
+		}, CPU{Model: "Broadcom BCM2711", Threads: 1}},
+		{"device tree in procfs", map[string]string{
+			"cpuinfo":                armCPUInfo("0x99", "0x001"),
+			"device-tree/compatible": "pine64,rockpro64\x00rockchip,rk3399\x00",
+		}, nil, CPU{Model: "Rockchip RK3399", Threads: 1}},
+		{"nothing known", map[string]string{"cpuinfo": "processor\t: 0\n"}, nil, CPU{Threads: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc, sys := t.TempDir(), t.TempDir()
+			writeFiles(t, proc, tt.proc)
+			writeFiles(t, sys, tt.sys)
+			got, err := readCPUInfo(proc, sys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("readCPUInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := readCPUInfo(t.TempDir(), t.TempDir()); err == nil {
+		t.Error("expected an error for a missing cpuinfo")
+	}
+}
+
+func TestCleanCPUModel(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Intel(R) Core(TM) i7-8550U CPU @ 1.80GHz", "Intel Core i7-8550U"},
+		{"AMD Ryzen 9 5950X 16-Core Processor", "AMD Ryzen 9 5950X"},
+		{"AMD EPYC 7763 64-Core Processor  ", "AMD EPYC 7763"},
+		{"Intel(R) Xeon(R) CPU E5-2680 v4 @ 2.40GHz", "Intel Xeon E5-2680 v4"},
+		{"ARM Cortex-A72", "ARM Cortex-A72"},
+	}
+	for _, tt := range tests {
+		if got := cleanCPUModel(tt.in); got != tt.want {
+			t.Errorf("cleanCPUModel(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReadCPUTimes(t *testing.T) {
+	tests := []struct {
+		name    string
+		stat    string
+		idle    uint64
+		total   uint64
+		wantErr bool
+	}{
+		// user nice system idle iowait irq softirq steal guest guest_nice
+		{"full", "cpu  100 10 50 800 40 0 0 0 30 5\ncpu0 50 5 25 400 20 0 0 0 15 2\n", 840, 1000, false},
+		{"old kernel", "cpu  100 0 100 800\n", 800, 1000, false},
+		{"no cpu line", "intr 12345\n", 0, 0, true},
+		{"malformed", "cpu  100 x 50 800 40\n", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, map[string]string{"stat": tt.stat})
+			idle, total, err := readCPUTimes(root)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readCPUTimes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if idle != tt.idle || total != tt.total {
+				t.Errorf("readCPUTimes() = %d, %d, want %d, %d", idle, total, tt.idle, tt.total)
+			}
+		})
+	}
+}
+
+func TestCPUUsageDelta(t *testing.T) {
+	before, after := t.TempDir(), t.TempDir()
+	writeFiles(t, before, map[string]string{"stat": "cpu  100 0 100 800 0 0 0 0 0 0\n"})
+	writeFiles(t, after, map[string]string{"stat": "cpu  175 0 125 900 0 0 0 0 0 0\n"})
+	idle1, total1, err := readCPUTimes(before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idle2, total2, err := readCPUTimes(after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 100 busy and 100 idle jiffies in between
+	if got := busyPercent(idle1, total1, idle2, total2); got != 50 {
+		t.Errorf("busyPercent() = %v, want 50", got)
+	}
+	if got := busyPercent(idle2, total2, idle2, total2); got != 0 {
+		t.Errorf("busyPercent() without progress = %v, want 0", got)
+	}
+}
+
+func TestCPUUsage(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"stat": "cpu  100 0 100 800 0 0 0 0 0 0\n"})
+
+	if got, err := cpuUsage(context.Background(), root, time.Millisecond); err != nil || got != 0 {
+		t.Errorf("cpuUsage() of an unchanged stat = %v, %v, want 0", got, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cpuUsage(ctx, root, time.Hour); err == nil {
+		t.Error("expected an error from a cancelled context")
+	}
+}
@@ -0,0 +1,192 @@
+package system
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+func init() {
+	Register(NewModule("de", "DE", func(ctx context.Context) (Value, error) {
+		return Value{Text: desktopEnvironment()}, nil
+	}))
+	Register(NewModule("wm", "WM", func(ctx context.Context) (Value, error) {
+		if isHeadless() {
+			return Value{}, nil
+		}
+		return Value{Text: windowManager(procRoot)}, nil
+	}))
+}
+
+// isHeadless reports a session with no graphical display, such as SSH.
+// Only X11 and Wayland systems are checked; macOS and Windows always have
+// their own display server.
+func isHeadless() bool {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		return false
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" || os.Getenv("DISPLAY") != "" {
+		return false
+	}
+	switch os.Getenv("XDG_SESSION_TYPE") {
+	case "wayland", "x11":
+		return false
+	}
+	return true
+}
+
+// displayServer returns "Wayland", "X11" or "".
+func displayServer() string {
+	switch {
+	case os.Getenv("XDG_SESSION_TYPE") == "wayland", os.Getenv("WAYLAND_DISPLAY") != "":
+		return "Wayland"
+	case os.Getenv("XDG_SESSION_TYPE") == "x11", os.Getenv("DISPLAY") != "":
+		return "X11"
+	}
+	return ""
+}
+
+// desktopNames maps XDG_CURRENT_DESKTOP and DESKTOP_SESSION values to
+// display names.
+var desktopNames = map[string]string{
+	"kde":           "KDE Plasma",
+	"plasma":        "KDE Plasma",
+	"plasmawayland": "KDE Plasma",
+	"gnome":         "GNOME",
+	"gnome-xorg":    "GNOME",
+	"ubuntu":        "GNOME",
+	"xfce":          "Xfce",
+	"xfce4":         "Xfce",
+	"x-cinnamon":    "Cinnamon",
+	"cinnamon":      "Cinnamon",
+	"mate":          "MATE",
+	"lxqt":          "LXQt",
+	"lxde":          "LXDE",
+	"budgie":        "Budgie",
+	"budgie:gnome":  "Budgie",
+	"pantheon":      "Pantheon",
+	"deepin":        "Deepin",
+	"unity":         "Unity",
+	"enlightenment": "Enlightenment",
+	"cosmic":        "COSMIC",
+}
+
+// desktopEnvironment describes the desktop, e.g. "GNOME 46 (Wayland)", or
+// "headless" when there is no display at all.
+func desktopEnvironment() string {
+	if runtime.GOOS == "darwin" {
+		return "Aqua"
+	}
+	if isHeadless() {
+		return "headless"
+	}
+
+	name := ""
+	if current := os.Getenv("XDG_CURRENT_DESKTOP"); current != "" {
+		// A list such as "ubuntu:GNOME"; the last entry is the most generic
+		parts := strings.Split(current, ":")
+		name = parts[len(parts)-1]
+	} else {
+		name = os.Getenv("DESKTOP_SESSION")
+	}
+	if name == "" {
+		return ""
+	}
+	if pretty, ok := desktopNames[strings.ToLower(name)]; ok {
+		name = pretty
+	}
+
+	if version := desktopVersion(name); version != "" {
+		name += " " + version
+	}
+	if server := displayServer(); server != "" {
+		name += " (" + server + ")"
+	}
+	return name
+}
+
+// desktopVersion reads versions that are available without spawning a
+// process.
+func desktopVersion(name string) string {
+	switch name {
+	case "KDE Plasma":
+		return os.Getenv("KDE_SESSION_VERSION")
+	case "GNOME":
+		return gnomeVersion(filepath.Join(rootDir, "usr/share/gnome/gnome-version.xml"))
+	}
+	return ""
+}
+
+func gnomeVersion(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var v struct {
+		Platform string `xml:"platform"`
+		Minor    string `xml:"minor"`
+		Micro    string `xml:"micro"`
+	}
+	if err := xml.Unmarshal(data, &v); err != nil || v.Platform == "" {
+		return ""
+	}
+	version := v.Platform
+	if v.Minor != "" {
+		version += "." + v.Minor
+	}
+	return version
+}
+
+// windowManagers maps process names, as truncated to 15 characters in
+// /proc/<pid>/comm, to window manager and compositor names.
+var windowManagers = map[string]string{
+	"sway":            "Sway",
+	"Hyprland":        "Hyprland",
+	"kwin_wayland":    "KWin",
+	"kwin_x11":        "KWin",
+	"kwin":            "KWin",
+	"gnome-shell":     "Mutter",
+	"mutter":          "Mutter",
+	"muffin":          "Muffin",
+	"marco":           "Marco",
+	"xfwm4":           "Xfwm4",
+	"i3":              "i3",
+	"bspwm":           "bspwm",
+	"openbox":         "Openbox",
+	"awesome":         "awesome",
+	"dwm":             "dwm",
+	"dwl":             "dwl",
+	"herbstluftwm":    "herbstluftwm",
+	"qtile":           "Qtile",
+	"river":           "river",
+	"wayfire":         "Wayfire",
+	"labwc":           "labwc",
+	"niri":            "niri",
+	"weston":          "Weston",
+	"fluxbox":         "Fluxbox",
+	"icewm":           "IceWM",
+	"enlightenment":   "Enlightenment",
+	"spectrwm":        "spectrwm",
+	"cage":            "Cage",
+	"cosmic-comp":     "cosmic-comp",
+	"xmonad":          "xmonad",
+	"xmonad-x86_64-l": "xmonad",
+}
+
+// windowManager scans running processes for a known window manager.
+func windowManager(root string) string {
+	comms, _ := filepath.Glob(filepath.Join(root, "[0-9]*", "comm"))
+	for _, path := range comms {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if name, ok := windowManagers[strings.TrimSpace(string(data))]; ok {
+			return name
+		}
+	}
+	return ""
+}
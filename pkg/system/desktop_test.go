@@ -0,0 +1,148 @@
+package system
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// desktopEnv lists every variable desktop detection reads, so each case
+// starts from a clean environment.
+var desktopEnv = []string{
+	"WAYLAND_DISPLAY", "DISPLAY", "XDG_SESSION_TYPE", "XDG_CURRENT_DESKTOP",
+	"DESKTOP_SESSION", "KDE_SESSION_VERSION",
+}
+
+func TestDesktopEnvironment(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("X11 and Wayland detection only")
+	}
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"usr/share/gnome/gnome-version.xml": `<?xml version="1.0"?>
+<gnome-version>
+  <platform>46</platform>
+  <minor>0</minor>
+  <micro>1</micro>
+</gnome-version>
+`,
+	})
+	setRoot(t, &rootDir, root)
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		want     string
+		headless bool
+	}{
+		{"headless", nil, "headless", true},
+		{"GNOME on Wayland", map[string]string{
+			"XDG_CURRENT_DESKTOP": "ubuntu:GNOME",
+			"XDG_SESSION_TYPE":    "wayland",
+			"WAYLAND_DISPLAY":     "wayland-0",
+		}, "GNOME 46.0 (Wayland)", false},
+		{"KDE on X11", map[string]string{
+			"XDG_CURRENT_DESKTOP": "KDE",
+			"KDE_SESSION_VERSION": "6",
+			"DISPLAY":             ":0",
+		}, "KDE Plasma 6 (X11)", false},
+		{"DESKTOP_SESSION", map[string]string{
+			"DESKTOP_SESSION": "xfce",
+			"DISPLAY":         ":0",
+		}, "Xfce (X11)", false},
+		{"unknown desktop", map[string]string{
+			"XDG_CURRENT_DESKTOP": "Hyprland",
+			"WAYLAND_DISPLAY":     "wayland-1",
+		}, "Hyprland (Wayland)", false},
+		{"session type only", map[string]string{
+			"XDG_SESSION_TYPE": "x11",
+		}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range desktopEnv {
+				t.Setenv(name, tt.env[name])
+			}
+			if got := isHeadless(); got != tt.headless {
+				t.Errorf("isHeadless() = %v, want %v", got, tt.headless)
+			}
+			if got := desktopEnvironment(); got != tt.want {
+				t.Errorf("desktopEnvironment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisplayServer(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{nil, ""},
+		{map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, "Wayland"},
+		{map[string]string{"XDG_SESSION_TYPE": "wayland"}, "Wayland"},
+		{map[string]string{"DISPLAY": ":1"}, "X11"},
+		{map[string]string{"XDG_SESSION_TYPE": "x11"}, "X11"},
+	}
+	for _, tt := range tests {
+		for _, name := range desktopEnv {
+			t.Setenv(name, tt.env[name])
+		}
+		if got := displayServer(); got != tt.want {
+			t.Errorf("displayServer() with %v = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestWindowManager(t *testing.T) {
+	tests := []struct {
+		name  string
+		comms map[string]string
+		want  string
+	}{
+		{"sway", map[string]string{
+			"1/comm":    "systemd\n",
+			"812/comm":  "sway\n",
+			"1204/comm": "zsh\n",
+		}, "Sway"},
+		{"truncated comm", map[string]string{
+			"1/comm":   "systemd\n",
+			"933/comm": "xmonad-x86_64-l\n",
+		}, "xmonad"},
+		{"none", map[string]string{
+			"1/comm":   "systemd\n",
+			"412/comm": "sshd\n",
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			// Non-process entries of /proc are ignored
+			writeFiles(t, root, map[string]string{"self/comm": "kwin_x11\n"})
+			writeFiles(t, root, tt.comms)
+			if got := windowManager(root); got != tt.want {
+				t.Errorf("windowManager() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGnomeVersion(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"full.xml":  "<gnome-version><platform>3</platform><minor>38</minor><micro>5</micro></gnome-version>",
+		"empty.xml": "<gnome-version></gnome-version>",
+		"bad.xml":   "not xml",
+	})
+	tests := []struct{ file, want string }{
+		{"full.xml", "3.38"},
+		{"empty.xml", ""},
+		{"bad.xml", ""},
+		{"missing.xml", ""},
+	}
+	for _, tt := range tests {
+		if got := gnomeVersion(filepath.Join(root, tt.file)); got != tt.want {
+			t.Errorf("gnomeVersion(%s) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
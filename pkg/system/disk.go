@@ -0,0 +1,143 @@
+package system
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register(NewModule("disk", "Disk", func(ctx context.Context) (Value, error) {
+		mounts, err := mountTable()
+		if err != nil {
+			return Value{}, err
+		}
+
+		var value Value
+		for _, m := range filterMounts(mounts, diskMounts) {
+			if ctx.Err() != nil {
+				// A hung network filesystem; the rest would wait too
+				break
+			}
+			usage, err := diskUsage(m.Path)
+			if err != nil || usage.Total == 0 {
+				continue
+			}
+			disk := Disk{Path: m.Path, Device: m.Device, Type: m.Type, Usage: usage}
+			value.Items = append(value.Items, Item{Name: m.Path, Text: disk.String(), Data: disk})
+		}
+		return value, nil
+	}))
+}
+
+// diskMounts, when set, lists the only mount points the disk module shows.
+var diskMounts []string
+
+// SetDiskMounts restricts the disk module to the given mount points, in
+// that order. An empty list shows every real filesystem.
+func SetDiskMounts(mounts []string) {
+	diskMounts = mounts
+}
+
+// Mount is one entry of the mount table.
+type Mount struct {
+	Device string
+	Path   string
+	Type   string
+}
+
+// Disk is the space used on one mounted filesystem.
+type Disk struct {
+	Path   string `json:"path"`
+	Device string `json:"device"`
+	Type   string `json:"type"`
+	Usage
+}
+
+// String formats the disk like "112.30 GiB / 467.89 GiB (24%) - ext4".
+func (d Disk) String() string {
+	return d.Usage.String() + " - " + d.Type
+}
+
+// ignoredFSTypes are virtual, in-memory and image filesystems that are not
+// shown unless their mount point is asked for.
+var ignoredFSTypes = map[string]bool{
+	"autofs": true, "binfmt_misc": true, "bpf": true, "cgroup": true,
+	"cgroup2": true, "configfs": true, "debugfs": true, "devfs": true,
+	"devpts": true, "devtmpfs": true, "efivarfs": true, "fdescfs": true,
+	"fusectl": true, "fuse.gvfsd-fuse": true, "fuse.portal": true,
+	"hugetlbfs": true, "linprocfs": true, "linsysfs": true, "mqueue": true,
+	"nsfs": true, "nullfs": true, "overlay": true, "proc": true,
+	"procfs": true, "pstore": true, "ramfs": true, "rpc_pipefs": true,
+	"securityfs": true, "squashfs": true, "sysfs": true, "tmpfs": true,
+	"tracefs": true,
+}
+
+// filterMounts picks the mounts to show. With an allowlist, those mount
+// points are returned in the given order; otherwise every real filesystem,
+// once per device so that bind mounts are not counted twice.
+func filterMounts(mounts []Mount, allow []string) []Mount {
+	var picked []Mount
+	if len(allow) > 0 {
+		for _, path := range allow {
+			for _, m := range mounts {
+				if m.Path == path {
+					picked = append(picked, m)
+					break
+				}
+			}
+		}
+		return picked
+	}
+
+	seen := map[string]bool{}
+	for _, m := range mounts {
+		if ignoredFSTypes[m.Type] || seen[m.Device] {
+			continue
+		}
+		// macOS mounts the read-only system snapshot and helper volumes
+		// here; the data volume is shown as /System/Volumes/Data
+		if strings.HasPrefix(m.Path, "/System/Volumes/") && m.Path != "/System/Volumes/Data" {
+			continue
+		}
+		seen[m.Device] = true
+		picked = append(picked, m)
+	}
+	return picked
+}
+
+// parseMounts reads the fstab-style format of /proc/self/mounts, in which
+// spaces and other special characters are octal escapes such as "\040".
+func parseMounts(data string) []Mount {
+	var mounts []Mount
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		mounts = append(mounts, Mount{
+			Device: unescapeMount(fields[0]),
+			Path:   unescapeMount(fields[1]),
+			Type:   fields[2],
+		})
+	}
+	return mounts
+}
+
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				sb.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,39 @@
+//go:build darwin || freebsd || dragonfly
+
+package system
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+func mountTable() ([]Mount, error) {
+	n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, fmt.Errorf("error reading mounts: %v", err)
+	}
+	buf := make([]unix.Statfs_t, n)
+	if n, err = unix.Getfsstat(buf, unix.MNT_NOWAIT); err != nil {
+		return nil, fmt.Errorf("error reading mounts: %v", err)
+	}
+
+	mounts := make([]Mount, 0, n)
+	for _, st := range buf[:n] {
+		mounts = append(mounts, Mount{
+			Device: unix.ByteSliceToString(st.Mntfromname[:]),
+			Path:   unix.ByteSliceToString(st.Mntonname[:]),
+			Type:   unix.ByteSliceToString(st.Fstypename[:]),
+		})
+	}
+	return mounts, nil
+}
+
+func diskUsage(path string) (Usage, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	size := uint64(st.Bsize)
+	return NewUsage(uint64(st.Blocks-st.Bfree)*size, uint64(st.Blocks)*size), nil
+}
@@ -0,0 +1,27 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+func mountTable() ([]Mount, error) {
+	data, err := os.ReadFile(filepath.Join(procRoot, "self/mounts"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading mounts: %v", err)
+	}
+	return parseMounts(string(data)), nil
+}
+
+func diskUsage(path string) (Usage, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	// Blocks are counted in fragments; Bsize is only the preferred I/O size
+	size := uint64(st.Frsize)
+	return NewUsage((st.Blocks-st.Bfree)*size, st.Blocks*size), nil
+}
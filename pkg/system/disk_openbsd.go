@@ -0,0 +1,37 @@
+package system
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+func mountTable() ([]Mount, error) {
+	n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, fmt.Errorf("error reading mounts: %v", err)
+	}
+	buf := make([]unix.Statfs_t, n)
+	if n, err = unix.Getfsstat(buf, unix.MNT_NOWAIT); err != nil {
+		return nil, fmt.Errorf("error reading mounts: %v", err)
+	}
+
+	mounts := make([]Mount, 0, n)
+	for _, st := range buf[:n] {
+		mounts = append(mounts, Mount{
+			Device: unix.ByteSliceToString(st.F_mntfromname[:]),
+			Path:   unix.ByteSliceToString(st.F_mntonname[:]),
+			Type:   unix.ByteSliceToString(st.F_fstypename[:]),
+		})
+	}
+	return mounts, nil
+}
+
+func diskUsage(path string) (Usage, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	size := uint64(st.F_bsize)
+	return NewUsage((st.F_blocks-st.F_bfree)*size, st.F_blocks*size), nil
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || dragonfly || openbsd)
+
+package system
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func mountTable() ([]Mount, error) {
+	return nil, fmt.Errorf("disks not supported on %s", runtime.GOOS)
+}
+
+func diskUsage(path string) (Usage, error) {
+	return Usage{}, fmt.Errorf("disks not supported on %s", runtime.GOOS)
+}
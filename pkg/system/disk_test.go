@@ -0,0 +1,67 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMounts(t *testing.T) {
+	data := `/dev/nvme0n1p2 / ext4 rw,relatime 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sdb1 /mnt/My\040Files vfat rw 0 0
+
+short line
+`
+	want := []Mount{
+		{Device: "/dev/nvme0n1p2", Path: "/", Type: "ext4"},
+		{Device: "proc", Path: "/proc", Type: "proc"},
+		{Device: "/dev/sdb1", Path: "/mnt/My Files", Type: "vfat"},
+	}
+	if got := parseMounts(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMounts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnescapeMount(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"/home", "/home"},
+		{`/mnt/My\040Files`, "/mnt/My Files"},
+		{`/mnt/tab\011end\040`, "/mnt/tab\tend "},
+		{`/mnt/back\134slash`, `/mnt/back\slash`},
+		{`/mnt/not\08escape`, `/mnt/not\08escape`},
+		{`/mnt/short\04`, `/mnt/short\04`},
+	}
+	for _, tt := range tests {
+		if got := unescapeMount(tt.in); got != tt.want {
+			t.Errorf("unescapeMount(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFilterMounts(t *testing.T) {
+	mounts := []Mount{
+		{Device: "/dev/nvme0n1p2", Path: "/", Type: "ext4"},
+		{Device: "tmpfs", Path: "/tmp", Type: "tmpfs"},
+		{Device: "overlay", Path: "/var/lib/docker/overlay2/abc/merged", Type: "overlay"},
+		{Device: "/dev/nvme0n1p1", Path: "/boot", Type: "vfat"},
+		{Device: "/dev/nvme0n1p3", Path: "/home", Type: "btrfs"},
+		{Device: "/dev/nvme0n1p3", Path: "/srv/home", Type: "btrfs"},
+		{Device: "/dev/disk3s1s1", Path: "/System/Volumes/Preboot", Type: "apfs"},
+		{Device: "/dev/disk3s5", Path: "/System/Volumes/Data", Type: "apfs"},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		want := []Mount{mounts[0], mounts[3], mounts[4], mounts[7]}
+		if got := filterMounts(mounts, nil); !reflect.DeepEqual(got, want) {
+			t.Errorf("filterMounts() = %+v, want %+v", got, want)
+		}
+	})
+	t.Run("allowlist", func(t *testing.T) {
+		// In the given order, including otherwise ignored types, and
+		// skipping mount points that don't exist
+		want := []Mount{mounts[4], mounts[1], mounts[0]}
+		if got := filterMounts(mounts, []string{"/home", "/tmp", "/missing", "/"}); !reflect.DeepEqual(got, want) {
+			t.Errorf("filterMounts() = %+v, want %+v", got, want)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+func init() {
+	Register(NewModule("os", "OS", func(ctx context.Context) (Value, error) {
+		d := DetectDistro(ctx)
+		text := strings.TrimSpace(d.PrettyName + " " + d.Arch)
+		if env := detectVirtualization(rootDir, procRoot, sysRoot).String(); env != "" {
+			text += " (" + env + ")"
+		}
+		return Value{Text: text, Data: d}, nil
+	}))
+}
+
+// rootDir is prefixed to absolute paths such as /etc/os-release so
+// detection can run against a fixture tree.
+var rootDir = "/"
+
+// Distro identifies the operating system distribution.
+type Distro struct {
+	// Name is the distribution name without version, e.g. "Ubuntu"
+	Name string `json:"name"`
+	// PrettyName is the full human-readable name, e.g. "Ubuntu 24.04.1 LTS"
+	PrettyName string `json:"pretty_name"`
+	// ID is a lower-case machine-readable identifier, e.g. "ubuntu"
+	ID string `json:"id"`
+	// IDLike lists distributions this one derives from, closest first
+	IDLike []string `json:"id_like,omitempty"`
+	// VersionID is the machine-readable version, e.g. "24.04"
+	VersionID string `json:"version_id,omitempty"`
+	// Arch is the hardware architecture, e.g. "x86_64"
+	Arch string `json:"arch"`
+}
+
+// IDs returns ID followed by IDLike, for lookups that fall back to a
+// parent distribution.
+func (d Distro) IDs() []string {
+	if d.ID == "" {
+		return d.IDLike
+	}
+	return append([]string{d.ID}, d.IDLike...)
+}
+
+// DetectDistro identifies the running system from os-release, then
+// lsb_release, then sw_vers on macOS, falling back to the kernel name.
+func DetectDistro(ctx context.Context) Distro {
+	var d Distro
+	switch {
+	case readOSRelease(&d):
+	case runtime.GOOS == "darwin" && readSwVers(ctx, &d):
+	case readLSBRelease(ctx, &d):
+	default:
+		d.Name = runtime.GOOS
+		d.ID = runtime.GOOS
+		if sysname, release, err := kernelName(); err == nil {
+			d.Name = sysname
+			d.ID = strings.ToLower(sysname)
+			d.VersionID = release
+		}
+	}
+
+	if d.PrettyName == "" {
+		d.PrettyName = strings.TrimSpace(d.Name + " " + d.VersionID)
+	}
+	if d.Name == "" {
+		d.Name = d.PrettyName
+	}
+	d.Arch = machineArch()
+	return d
+}
+
+// readOSRelease fills d from the first os-release file found.
+func readOSRelease(d *Distro) bool {
+	for _, path := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		file, err := os.Open(filepath.Join(rootDir, path))
+		if err != nil {
+			continue
+		}
+		fields := parseOSRelease(file)
+		file.Close()
+
+		d.Name = fields["NAME"]
+		d.PrettyName = fields["PRETTY_NAME"]
+		d.ID = fields["ID"]
+		d.IDLike = strings.Fields(fields["ID_LIKE"])
+		d.VersionID = fields["VERSION_ID"]
+		if d.ID == "" {
+			// os-release(5) says to assume "linux" when ID is missing
+			d.ID = "linux"
+		}
+		if d.Name == "" {
+			d.Name = "Linux"
+		}
+		return true
+	}
+	return false
+}
+
+// parseOSRelease reads the shell-like KEY=value assignments of an
+// os-release file, removing quotes and backslash escapes.
+func parseOSRelease(r io.Reader) map[string]string {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		fields[key] = unquote(value)
+	}
+	return fields
+}
+
+func unquote(value string) string {
+	if len(value) < 2 || (value[0] != '"' && value[0] != '\'') || value[len(value)-1] != value[0] {
+		return value
+	}
+	quote := value[0]
+	value = value[1 : len(value)-1]
+	if quote == '\'' {
+		return value
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+		}
+		sb.WriteByte(value[i])
+	}
+	return sb.String()
+}
+
+// readLSBRelease fills d from `lsb_release -a`.
+func readLSBRelease(ctx context.Context, d *Distro) bool {
+	out, err := exec.CommandContext(ctx, "lsb_release", "-a").Output()
+	if err != nil {
+		return false
+	}
+	fields := parseColonFields(string(out))
+	if fields["Distributor ID"] == "" {
+		return false
+	}
+	d.Name = fields["Distributor ID"]
+	d.ID = strings.ToLower(strings.ReplaceAll(d.Name, " ", ""))
+	d.PrettyName = fields["Description"]
+	d.VersionID = fields["Release"]
+	return true
+}
+
+// readSwVers fills d from macOS's `sw_vers`.
+func readSwVers(ctx context.Context, d *Distro) bool {
+	out, err := exec.CommandContext(ctx, "sw_vers").Output()
+	if err != nil {
+		return false
+	}
+	fields := parseColonFields(string(out))
+	if fields["ProductVersion"] == "" {
+		return false
+	}
+	d.Name = "macOS"
+	d.ID = "macos"
+	d.VersionID = fields["ProductVersion"]
+	d.PrettyName = "macOS " + d.VersionID
+	if build := fields["BuildVersion"]; build != "" {
+		d.PrettyName += " (" + build + ")"
+	}
+	return true
+}
+
+// parseColonFields parses "Key: value" lines.
+func parseColonFields(text string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(text, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok {
+			fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,152 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// setRoot points a fixture root variable such as rootDir at dir for the
+// duration of the test.
+func setRoot(t *testing.T, root *string, dir string) {
+	t.Helper()
+	old := *root
+	*root = dir
+	t.Cleanup(func() { *root = old })
+}
+
+// writeFiles creates files under dir from a map of relative paths to
+// contents.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseOSRelease(t *testing.T) {
+	input := `# comment
+NAME="Ubuntu"
+PRETTY_NAME="Ubuntu 24.04.1 LTS"
+ID=ubuntu
+ID_LIKE=debian
+VERSION_ID='24.04'
+ESCAPED="say \"hi\" to \\ and \$HOME"
+UNTERMINATED="oops
+not an assignment
+`
+	want := map[string]string{
+		"NAME":         "Ubuntu",
+		"PRETTY_NAME":  "Ubuntu 24.04.1 LTS",
+		"ID":           "ubuntu",
+		"ID_LIKE":      "debian",
+		"VERSION_ID":   "24.04",
+		"ESCAPED":      `say "hi" to \ and $HOME`,
+		"UNTERMINATED": `"oops`,
+	}
+	if got := parseOSRelease(strings.NewReader(input)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseOSRelease() = %#v, want %#v", got, want)
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`plain`, "plain"},
+		{`"double quoted"`, "double quoted"},
+		{`'single \"raw\"'`, `single \"raw\"`},
+		{`"a\"b"`, `a"b`},
+		{`"`, `"`},
+		{`"mismatched'`, `"mismatched'`},
+	}
+	for _, tt := range tests {
+		if got := unquote(tt.in); got != tt.want {
+			t.Errorf("unquote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReadOSRelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    Distro
+	}{
+		{
+			name:    "quoted pretty name",
+			path:    "etc/os-release",
+			content: "NAME=\"Fedora Linux\"\nPRETTY_NAME=\"Fedora Linux 40 (Workstation Edition)\"\nID=fedora\nVERSION_ID=40\n",
+			want:    Distro{Name: "Fedora Linux", PrettyName: "Fedora Linux 40 (Workstation Edition)", ID: "fedora", IDLike: []string{}, VersionID: "40"},
+		},
+		{
+			name:    "id like list",
+			path:    "etc/os-release",
+			content: "NAME=\"Linux Mint\"\nID=linuxmint\nID_LIKE=\"ubuntu debian\"\n",
+			want:    Distro{Name: "Linux Mint", ID: "linuxmint", IDLike: []string{"ubuntu", "debian"}},
+		},
+		{
+			name:    "missing id",
+			path:    "usr/lib/os-release",
+			content: "PRETTY_NAME=\"Custom\"\n",
+			want:    Distro{Name: "Linux", PrettyName: "Custom", ID: "linux", IDLike: []string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{tt.path: tt.content})
+			setRoot(t, &rootDir, dir)
+
+			var d Distro
+			if !readOSRelease(&d) {
+				t.Fatal("readOSRelease() = false")
+			}
+			if !reflect.DeepEqual(d, tt.want) {
+				t.Errorf("readOSRelease() = %#v, want %#v", d, tt.want)
+			}
+			if ids := d.IDs(); ids[0] != tt.want.ID {
+				t.Errorf("IDs() = %v, want %s first", ids, tt.want.ID)
+			}
+		})
+	}
+}
+
+func TestReadOSReleaseMissing(t *testing.T) {
+	setRoot(t, &rootDir, t.TempDir())
+	var d Distro
+	if readOSRelease(&d) {
+		t.Error("readOSRelease() = true without an os-release file")
+	}
+}
+
+func TestOSModuleData(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"etc/os-release": "NAME=\"Linux Mint\"\nID=linuxmint\nID_LIKE=\"ubuntu debian\"\nPRETTY_NAME=\"Linux Mint 22\"\n",
+	})
+	setRoot(t, &rootDir, root)
+	setRoot(t, &procRoot, t.TempDir())
+	setRoot(t, &sysRoot, t.TempDir())
+	t.Setenv("container", "")
+
+	report := Collect(context.Background(), []string{"os"}, Options{})
+	d, ok := report.Data("os").(Distro)
+	if !ok {
+		t.Fatalf("os data = %#v, want a Distro", report.Data("os"))
+	}
+	if want := []string{"linuxmint", "ubuntu", "debian"}; !reflect.DeepEqual(d.IDs(), want) {
+		t.Errorf("IDs() = %v, want %v", d.IDs(), want)
+	}
+	if report.Data("kernel") != nil {
+		t.Error("Data() of a module that was not run should be nil")
+	}
+}
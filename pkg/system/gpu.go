@@ -0,0 +1,191 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register(NewModule("gpu", "GPU", func(ctx context.Context) (Value, error) {
+		gpus, err := listGPUs(sysRoot)
+		if err != nil {
+			return Value{}, err
+		}
+		names := make([]string, len(gpus))
+		for i, gpu := range gpus {
+			names[i] = gpu.String()
+		}
+		return Value{Text: strings.Join(names, ", ")}, nil
+	}))
+}
+
+// sysRoot is where sysfs is mounted. It is a variable so collectors can be
+// pointed at a fixture tree.
+var sysRoot = "/sys"
+
+// pciIDsPaths are the usual locations of the pci.ids database.
+var pciIDsPaths = []string{
+	"/usr/share/hwdata/pci.ids",
+	"/usr/share/misc/pci.ids",
+	"/usr/share/pci.ids",
+	"/var/lib/pciutils/pci.ids",
+}
+
+// pciVendors names common GPU vendors when no pci.ids database is
+// installed. It also supplies the short names shown instead of the full
+// company names from pci.ids.
+var pciVendors = map[string]string{
+	"1002": "AMD",
+	"1013": "Cirrus Logic",
+	"102b": "Matrox",
+	"10de": "NVIDIA",
+	"1234": "QEMU",
+	"14e4": "Broadcom",
+	"15ad": "VMware",
+	"1a03": "ASPEED",
+	"1af4": "Red Hat Virtio",
+	"1b36": "Red Hat",
+	"1de1": "Tekram",
+	"5143": "Qualcomm",
+	"80ee": "VirtualBox",
+	"8086": "Intel",
+}
+
+// GPU is a PCI display controller.
+type GPU struct {
+	Slot     string
+	VendorID string
+	DeviceID string
+	Vendor   string
+	Device   string
+	Driver   string
+}
+
+// String renders e.g. "NVIDIA GeForce RTX 3070 [nvidia]".
+func (g GPU) String() string {
+	name := g.Device
+	if name == "" {
+		name = "Device " + g.DeviceID
+	}
+	if g.Vendor != "" {
+		name = g.Vendor + " " + name
+	}
+	if g.Driver != "" {
+		name += " [" + g.Driver + "]"
+	}
+	return name
+}
+
+// listGPUs finds PCI devices of the display controller class (0x03) under
+// root/bus/pci/devices. A system without GPUs yields no entries.
+func listGPUs(root string) ([]GPU, error) {
+	devices, err := filepath.Glob(filepath.Join(root, "bus/pci/devices/*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var gpus []GPU
+	for _, dev := range devices {
+		class := readSysfsString(filepath.Join(dev, "class"))
+		if !strings.HasPrefix(class, "0x03") {
+			continue
+		}
+
+		gpu := GPU{
+			Slot:     filepath.Base(dev),
+			VendorID: strings.TrimPrefix(readSysfsString(filepath.Join(dev, "vendor")), "0x"),
+			DeviceID: strings.TrimPrefix(readSysfsString(filepath.Join(dev, "device")), "0x"),
+		}
+		if target, err := os.Readlink(filepath.Join(dev, "driver")); err == nil {
+			gpu.Driver = filepath.Base(target)
+		}
+		gpus = append(gpus, gpu)
+	}
+
+	if len(gpus) > 0 {
+		resolvePCINames(gpus)
+	}
+	return gpus, nil
+}
+
+// resolvePCINames fills in vendor and device names from pci.ids, falling
+// back to the built-in vendor table.
+func resolvePCINames(gpus []GPU) {
+	for _, path := range pciIDsPaths {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		lookupPCIIDs(bufio.NewScanner(file), gpus)
+		file.Close()
+		break
+	}
+
+	for i := range gpus {
+		if short, ok := pciVendors[gpus[i].VendorID]; ok {
+			gpus[i].Vendor = short
+		}
+		if gpus[i].Vendor == "" {
+			gpus[i].Vendor = fmt.Sprintf("Vendor %s", gpus[i].VendorID)
+		}
+	}
+}
+
+// lookupPCIIDs scans a pci.ids database. Vendor lines are "vvvv  Name" and
+// the device lines under them are "\tdddd  Name".
+func lookupPCIIDs(scanner *bufio.Scanner, gpus []GPU) {
+	vendor := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if strings.HasPrefix(line, "C ") {
+			// Device classes follow the vendor list
+			return
+		}
+
+		if line[0] != '\t' {
+			id, name, _ := strings.Cut(line, "  ")
+			vendor = id
+			for i := range gpus {
+				if gpus[i].VendorID == id {
+					gpus[i].Vendor = name
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "\t\t") {
+			continue
+		}
+
+		id, name, _ := strings.Cut(strings.TrimPrefix(line, "\t"), "  ")
+		for i := range gpus {
+			if gpus[i].VendorID == vendor && gpus[i].DeviceID == id {
+				gpus[i].Device = marketingName(name)
+			}
+		}
+	}
+}
+
+// marketingName prefers the bracketed product name of entries like
+// "GA104 [GeForce RTX 3070]".
+func marketingName(name string) string {
+	open := strings.LastIndex(name, "[")
+	if open >= 0 && strings.HasSuffix(name, "]") {
+		return name[open+1 : len(name)-1]
+	}
+	return name
+}
+
+func readSysfsString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,125 @@
+package system
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// pciDevice describes one device of a fake bus/pci/devices tree.
+type pciDevice struct {
+	slot, class, vendor, device, driver string
+}
+
+// fakeSysfs builds <root>/bus/pci/devices with a driver symlink into
+// <root>/bus/pci/drivers, as the kernel lays it out.
+func fakeSysfs(t *testing.T, devices []pciDevice) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, d := range devices {
+		dir := filepath.Join(root, "bus/pci/devices", d.slot)
+		writeFiles(t, dir, map[string]string{
+			"class":  d.class + "\n",
+			"vendor": d.vendor + "\n",
+			"device": d.device + "\n",
+		})
+		if d.driver != "" {
+			driver := filepath.Join(root, "bus/pci/drivers", d.driver)
+			if err := os.MkdirAll(driver, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Symlink(driver, filepath.Join(dir, "driver")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return root
+}
+
+func TestListGPUs(t *testing.T) {
+	old := pciIDsPaths
+	t.Cleanup(func() { pciIDsPaths = old })
+	pciIDsPaths = []string{"testdata/pci.ids"}
+
+	root := fakeSysfs(t, []pciDevice{
+		{"0000:00:1f.3", "0x040300", "0x8086", "0x51c8", "snd_hda_intel"},
+		{"0000:01:00.0", "0x030000", "0x10de", "0x2484", "nvidia"},
+		{"0000:00:02.0", "0x030000", "0x1234", "0x1111", ""},
+	})
+	gpus, err := listGPUs(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []GPU{
+		{Slot: "0000:00:02.0", VendorID: "1234", DeviceID: "1111", Vendor: "QEMU", Device: "QEMU Virtual Video Controller"},
+		{Slot: "0000:01:00.0", VendorID: "10de", DeviceID: "2484", Vendor: "NVIDIA", Device: "GeForce RTX 3070", Driver: "nvidia"},
+	}
+	if !reflect.DeepEqual(gpus, want) {
+		t.Errorf("listGPUs() = %+v, want %+v", gpus, want)
+	}
+	if got := gpus[1].String(); got != "NVIDIA GeForce RTX 3070 [nvidia]" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestListGPUsNone(t *testing.T) {
+	root := fakeSysfs(t, []pciDevice{
+		{"0000:00:1f.3", "0x040300", "0x8086", "0x51c8", "snd_hda_intel"},
+		{"0000:00:14.0", "0x0c0330", "0x8086", "0xa0ed", "xhci_hcd"},
+	})
+	gpus, err := listGPUs(root)
+	if err != nil || len(gpus) != 0 {
+		t.Errorf("listGPUs() = %v, %v, want none", gpus, err)
+	}
+	if gpus, err := listGPUs(t.TempDir()); err != nil || len(gpus) != 0 {
+		t.Errorf("listGPUs() without a PCI bus = %v, %v, want none", gpus, err)
+	}
+}
+
+func TestLookupPCIIDs(t *testing.T) {
+	data, err := os.ReadFile("testdata/pci.ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpus := []GPU{
+		{VendorID: "10de", DeviceID: "2484"},
+		{VendorID: "10de", DeviceID: "2786"},
+		{VendorID: "8086", DeviceID: "9a49"},
+		{VendorID: "1234", DeviceID: "1111"},
+		{VendorID: "10de", DeviceID: "ffff"},
+		{VendorID: "abcd", DeviceID: "0001"},
+	}
+	lookupPCIIDs(bufio.NewScanner(strings.NewReader(string(data))), gpus)
+
+	want := []struct{ vendor, device string }{
+		{"NVIDIA Corporation", "GeForce RTX 3070"},
+		{"NVIDIA Corporation", "GeForce RTX 4070"},
+		{"Intel Corporation", "Iris Xe Graphics"},
+		{"Technical Corp", "QEMU Virtual Video Controller"},
+		// The class section after "C " must not be read as vendors
+		{"NVIDIA Corporation", ""},
+		{"", ""},
+	}
+	for i, w := range want {
+		if gpus[i].Vendor != w.vendor || gpus[i].Device != w.device {
+			t.Errorf("gpu %s:%s = %q %q, want %q %q", gpus[i].VendorID, gpus[i].DeviceID, gpus[i].Vendor, gpus[i].Device, w.vendor, w.device)
+		}
+	}
+}
+
+func TestMarketingName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"GA104 [GeForce RTX 3070]", "GeForce RTX 3070"},
+		{"Navi 21 [Radeon RX 6800/6800 XT / 6900 XT]", "Radeon RX 6800/6800 XT / 6900 XT"},
+		{"QEMU Virtual Video Controller", "QEMU Virtual Video Controller"},
+		{"[unterminated", "[unterminated"},
+	}
+	for _, tt := range tests {
+		if got := marketingName(tt.in); got != tt.want {
+			t.Errorf("marketingName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register(NewModule("host", "Host", func(ctx context.Context) (Value, error) {
+		return Value{Text: hostModel()}, nil
+	}))
+	Register(NewModule("board", "Motherboard", func(ctx context.Context) (Value, error) {
+		dmi := readDMI(sysRoot)
+		return Value{Text: joinNames(prettyVendor(dmi.BoardVendor), dmi.BoardName), Data: dmi}, nil
+	}))
+	Register(NewModule("bios", "BIOS", func(ctx context.Context) (Value, error) {
+		dmi := readDMI(sysRoot)
+		text := joinNames(dmi.BIOSVendor, dmi.BIOSVersion)
+		if text != "" && dmi.BIOSDate != "" {
+			text += " (" + dmi.BIOSDate + ")"
+		}
+		return Value{Text: text, Data: dmi}, nil
+	}))
+}
+
+// DMI is the firmware's description of the machine, with placeholder
+// strings removed.
+type DMI struct {
+	SysVendor      string `json:"sys_vendor,omitempty"`
+	ProductName    string `json:"product_name,omitempty"`
+	ProductVersion string `json:"product_version,omitempty"`
+	BoardVendor    string `json:"board_vendor,omitempty"`
+	BoardName      string `json:"board_name,omitempty"`
+	BIOSVendor     string `json:"bios_vendor,omitempty"`
+	BIOSVersion    string `json:"bios_version,omitempty"`
+	BIOSDate       string `json:"bios_date,omitempty"`
+}
+
+// readDMI reads <root>/class/dmi/id. The files are missing on machines
+// without SMBIOS, such as most ARM boards.
+func readDMI(root string) DMI {
+	dir := filepath.Join(root, "class/dmi/id")
+	read := func(name string) string {
+		value := readSysfsString(filepath.Join(dir, name))
+		if isPlaceholder(value) {
+			return ""
+		}
+		return value
+	}
+	return DMI{
+		SysVendor:      read("sys_vendor"),
+		ProductName:    read("product_name"),
+		ProductVersion: read("product_version"),
+		BoardVendor:    read("board_vendor"),
+		BoardName:      read("board_name"),
+		BIOSVendor:     read("bios_vendor"),
+		BIOSVersion:    read("bios_version"),
+		BIOSDate:       read("bios_date"),
+	}
+}
+
+// dmiPlaceholders are strings vendors leave in fields they did not fill in.
+var dmiPlaceholders = []string{
+	"to be filled by o.e.m.", "default string", "system product name",
+	"system version", "system manufacturer", "system name", "not applicable",
+	"not specified", "none", "type1productconfigid", "o.e.m.", "oem", "x.x",
+	"0123456789", "invalid", "undefined", "unknown", "n/a", "chassis version",
+}
+
+func isPlaceholder(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return true
+	}
+	for _, p := range dmiPlaceholders {
+		if value == p {
+			return true
+		}
+	}
+	return false
+}
+
+// dmiHost names the machine, e.g. "Lenovo ThinkPad T14 Gen 3". Custom
+// built desktops have no product name, so the motherboard stands in.
+func dmiHost(dmi DMI) string {
+	vendor, product := prettyVendor(dmi.SysVendor), dmi.ProductName
+	switch {
+	case vendor == "Lenovo" && dmi.ProductVersion != "":
+		// Lenovo puts the machine type in product_name and the
+		// marketing name in product_version
+		product = dmi.ProductVersion
+	case product != "" && dmi.ProductVersion != "" && strings.IndexFunc(dmi.ProductVersion, isLetter) >= 0:
+		product += " " + dmi.ProductVersion
+	case product == "":
+		vendor, product = prettyVendor(dmi.BoardVendor), dmi.BoardName
+	}
+	if product == "" {
+		return ""
+	}
+	return joinNames(vendor, product)
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// deviceTreeModel reads the board name ARM firmware provides, e.g.
+// "Raspberry Pi 4 Model B Rev 1.4".
+func deviceTreeModel(procRoot, sysRoot string) string {
+	for _, path := range []string{
+		filepath.Join(sysRoot, "firmware/devicetree/base/model"),
+		filepath.Join(procRoot, "device-tree/model"),
+	} {
+		if data, err := os.ReadFile(path); err == nil {
+			return strings.TrimSpace(strings.TrimRight(string(data), "\x00"))
+		}
+	}
+	return ""
+}
+
+// vendorNames shortens the company names found in DMI tables.
+var vendorNames = map[string]string{
+	"asustek computer inc.":              "ASUS",
+	"dell inc.":                          "Dell",
+	"gigabyte technology co., ltd.":      "Gigabyte",
+	"hewlett-packard":                    "HP",
+	"lenovo":                             "Lenovo",
+	"micro-star international co., ltd.": "MSI",
+	"microsoft corporation":              "Microsoft",
+	"qemu":                               "QEMU",
+	"innotek gmbh":                       "innotek",
+	"apple inc.":                         "Apple",
+	"acer":                               "Acer",
+	"framework":                          "Framework",
+}
+
+func prettyVendor(vendor string) string {
+	if name, ok := vendorNames[strings.ToLower(vendor)]; ok {
+		return name
+	}
+	return vendor
+}
+
+// joinNames joins vendor and product, leaving out the vendor when the
+// product already starts with it.
+func joinNames(vendor, product string) string {
+	switch {
+	case vendor == "":
+		return product
+	case product == "":
+		return vendor
+	case strings.HasPrefix(strings.ToLower(product), strings.ToLower(vendor)):
+		return product
+	}
+	return vendor + " " + product
+}
@@ -0,0 +1,10 @@
+package system
+
+// hostModel names the machine from DMI, or from the device tree on boards
+// without it.
+func hostModel() string {
+	if host := dmiHost(readDMI(sysRoot)); host != "" {
+		return host
+	}
+	return deviceTreeModel(procRoot, sysRoot)
+}
@@ -0,0 +1,35 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package system
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// hostSysctls lists the vendor and product sysctls of each system.
+var hostSysctls = map[string][2]string{
+	"darwin":  {"", "hw.model"},
+	"netbsd":  {"machdep.dmi.system-vendor", "machdep.dmi.system-product"},
+	"openbsd": {"hw.vendor", "hw.product"},
+}
+
+// hostModel names the machine from sysctl, e.g. "MacBookPro18,3". FreeBSD
+// keeps SMBIOS in the kernel environment rather than sysctl, so it has no
+// host line.
+func hostModel() string {
+	keys, ok := hostSysctls[runtime.GOOS]
+	if !ok {
+		return ""
+	}
+	var dmi DMI
+	if keys[0] != "" {
+		dmi.SysVendor, _ = unix.Sysctl(keys[0])
+	}
+	dmi.ProductName, _ = unix.Sysctl(keys[1])
+	if isPlaceholder(dmi.ProductName) {
+		return ""
+	}
+	return dmiHost(dmi)
+}
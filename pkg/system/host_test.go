@@ -0,0 +1,97 @@
+package system
+
+import "testing"
+
+func TestDMIHost(t *testing.T) {
+	tests := []struct {
+		name string
+		dmi  map[string]string
+		want string
+	}{
+		{"placeholders", map[string]string{
+			"sys_vendor":      "To Be Filled By O.E.M.",
+			"product_name":    "To Be Filled By O.E.M.",
+			"product_version": "To Be Filled By O.E.M.",
+		}, ""},
+		{"Lenovo", map[string]string{
+			"sys_vendor":      "LENOVO",
+			"product_name":    "21AHCTO1WW",
+			"product_version": "ThinkPad T14 Gen 3",
+		}, "Lenovo ThinkPad T14 Gen 3"},
+		{"custom desktop", map[string]string{
+			"sys_vendor":      "System manufacturer",
+			"product_name":    "System Product Name",
+			"product_version": "System Version",
+			"board_vendor":    "ASUSTeK COMPUTER INC.",
+			"board_name":      "ROG STRIX B550-F GAMING",
+		}, "ASUS ROG STRIX B550-F GAMING"},
+		{"numeric version", map[string]string{
+			"sys_vendor":      "Micro-Star International Co., Ltd.",
+			"product_name":    "MS-7C56",
+			"product_version": "1.0",
+		}, "MSI MS-7C56"},
+		{"version with letters", map[string]string{
+			"sys_vendor":      "QEMU",
+			"product_name":    "Standard PC (Q35 + ICH9, 2009)",
+			"product_version": "pc-q35-8.2",
+		}, "QEMU Standard PC (Q35 + ICH9, 2009) pc-q35-8.2"},
+		{"product starts with vendor", map[string]string{
+			"sys_vendor":      "HP",
+			"product_name":    "HP EliteBook 840 G8 Notebook PC",
+			"product_version": "Type1ProductConfigId",
+		}, "HP EliteBook 840 G8 Notebook PC"},
+		{"no DMI", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			files := map[string]string{}
+			for name, content := range tt.dmi {
+				files["class/dmi/id/"+name] = content + "\n"
+			}
+			writeFiles(t, root, files)
+			if got := dmiHost(readDMI(root)); got != tt.want {
+				t.Errorf("dmiHost() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadDMIDropsPlaceholders(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"class/dmi/id/board_vendor": "Default string\n",
+		"class/dmi/id/board_name":   "  N/A  \n",
+		"class/dmi/id/bios_vendor":  "American Megatrends Inc.\n",
+		"class/dmi/id/bios_version": "1.40\n",
+		"class/dmi/id/bios_date":    "03/15/2023\n",
+	})
+	want := DMI{BIOSVendor: "American Megatrends Inc.", BIOSVersion: "1.40", BIOSDate: "03/15/2023"}
+	if got := readDMI(root); got != want {
+		t.Errorf("readDMI() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDeviceTreeModel(t *testing.T) {
+	t.Run("sysfs", func(t *testing.T) {
+		sys := t.TempDir()
+		writeFiles(t, sys, map[string]string{
+			"firmware/devicetree/base/model": "Raspberry Pi 4 Model B Rev 1.4\x00",
+		})
+		if got := deviceTreeModel(t.TempDir(), sys); got != "Raspberry Pi 4 Model B Rev 1.4" {
+			t.Errorf("deviceTreeModel() = %q", got)
+		}
+	})
+	t.Run("procfs", func(t *testing.T) {
+		proc := t.TempDir()
+		writeFiles(t, proc, map[string]string{"device-tree/model": "Pine64 RockPro64 v2.1\x00"})
+		if got := deviceTreeModel(proc, t.TempDir()); got != "Pine64 RockPro64 v2.1" {
+			t.Errorf("deviceTreeModel() = %q", got)
+		}
+	})
+	t.Run("missing", func(t *testing.T) {
+		if got := deviceTreeModel(t.TempDir(), t.TempDir()); got != "" {
+			t.Errorf("deviceTreeModel() = %q, want empty", got)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(NewModule("init", "Init", func(ctx context.Context) (Value, error) {
+		s := InitSystem{Name: initSystem(rootDir, procRoot)}
+		if s.Name == "" {
+			return Value{}, nil
+		}
+		if s.Name == "systemd" && failedUnitCounter != nil {
+			if n, err := failedUnitCounter.FailedUnits(ctx); err == nil {
+				s.FailedUnits = &n
+			}
+		}
+		return Value{Text: s.String(), Data: s}, nil
+	}))
+}
+
+// InitSystem is the service manager running as PID 1.
+type InitSystem struct {
+	Name string `json:"name"`
+	// FailedUnits is set when failed systemd units were counted
+	FailedUnits *int `json:"failed_units,omitempty"`
+}
+
+// String formats the init system like "systemd (2 failed units)".
+func (s InitSystem) String() string {
+	if s.FailedUnits == nil {
+		return s.Name
+	}
+	switch *s.FailedUnits {
+	case 0:
+		return s.Name
+	case 1:
+		return s.Name + " (1 failed unit)"
+	}
+	return fmt.Sprintf("%s (%d failed units)", s.Name, *s.FailedUnits)
+}
+
+// initNames maps the command name of PID 1 to the init system.
+var initNames = map[string]string{
+	"systemd":     "systemd",
+	"openrc-init": "OpenRC",
+	"runit":       "runit",
+	"runit-init":  "runit",
+	"s6-svscan":   "s6",
+	"dinit":       "dinit",
+	"shepherd":    "GNU Shepherd",
+}
+
+// initSystem identifies PID 1 from <procRoot>/1/comm. A plain "init" is
+// sysvinit or busybox, unless OpenRC left its state directory behind.
+func initSystem(root, procRoot string) string {
+	comm := readSysfsString(filepath.Join(procRoot, "1/comm"))
+	if name, ok := initNames[comm]; ok {
+		return name
+	}
+	if comm == "init" {
+		if _, err := os.Stat(filepath.Join(root, "run/openrc")); err == nil {
+			return "OpenRC"
+		}
+		return "SysVinit"
+	}
+	return comm
+}
+
+// FailedUnitCounter counts systemd units in the failed state.
+type FailedUnitCounter interface {
+	FailedUnits(ctx context.Context) (int, error)
+}
+
+// failedUnitCounter backs the failed unit count of the init module; nil
+// leaves it out.
+var failedUnitCounter FailedUnitCounter
+
+// SetFailedUnitCounter sets how the init module counts failed units.
+func SetFailedUnitCounter(c FailedUnitCounter) {
+	failedUnitCounter = c
+}
+
+// SystemctlCounter counts failed units by running systemctl.
+type SystemctlCounter struct {
+	Timeout time.Duration
+}
+
+// NewSystemctlCounter returns a counter that gives up after timeout.
+func NewSystemctlCounter(timeout time.Duration) *SystemctlCounter {
+	return &SystemctlCounter{Timeout: timeout}
+}
+
+func (c *SystemctlCounter) FailedUnits(ctx context.Context) (int, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	out, err := exec.CommandContext(ctx, "systemctl", "--failed", "--no-legend", "--plain").Output()
+	if err != nil {
+		return 0, fmt.Errorf("error running systemctl: %v", err)
+	}
+	return countFailedUnits(string(out)), nil
+}
+
+// countFailedUnits counts the unit lines of "systemctl --failed
+// --no-legend --plain".
+func countFailedUnits(out string) int {
+	n := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
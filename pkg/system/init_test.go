@@ -0,0 +1,118 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInitSystem(t *testing.T) {
+	tests := []struct {
+		name  string
+		comm  string
+		files map[string]string
+		want  string
+	}{
+		{"systemd", "systemd", nil, "systemd"},
+		{"openrc-init", "openrc-init", nil, "OpenRC"},
+		{"runit", "runit", nil, "runit"},
+		{"s6", "s6-svscan", nil, "s6"},
+		{"dinit", "dinit", nil, "dinit"},
+		{"init with openrc", "init", map[string]string{"run/openrc/softlevel": "default\n"}, "OpenRC"},
+		{"plain init", "init", nil, "SysVinit"},
+		{"unknown", "tini", nil, "tini"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, proc := t.TempDir(), t.TempDir()
+			writeFiles(t, proc, map[string]string{"1/comm": tt.comm + "\n"})
+			writeFiles(t, root, tt.files)
+			if got := initSystem(root, proc); got != tt.want {
+				t.Errorf("initSystem() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := initSystem(t.TempDir(), t.TempDir()); got != "" {
+		t.Errorf("initSystem() without /proc = %q, want empty", got)
+	}
+}
+
+// fakeCounter returns a fixed failed unit count or error.
+type fakeCounter struct {
+	n   int
+	err error
+}
+
+func (c fakeCounter) FailedUnits(ctx context.Context) (int, error) {
+	return c.n, c.err
+}
+
+func TestInitModule(t *testing.T) {
+	proc := t.TempDir()
+	writeFiles(t, proc, map[string]string{"1/comm": "systemd\n"})
+	setRoot(t, &procRoot, proc)
+	setRoot(t, &rootDir, t.TempDir())
+	t.Cleanup(func() { SetFailedUnitCounter(nil) })
+
+	intPtr := func(n int) *int { return &n }
+	tests := []struct {
+		name     string
+		counter  FailedUnitCounter
+		want     string
+		wantData InitSystem
+	}{
+		{"no counter", nil, "systemd", InitSystem{Name: "systemd"}},
+		{"none failed", fakeCounter{n: 0}, "systemd", InitSystem{Name: "systemd", FailedUnits: intPtr(0)}},
+		{"one failed", fakeCounter{n: 1}, "systemd (1 failed unit)", InitSystem{Name: "systemd", FailedUnits: intPtr(1)}},
+		{"several failed", fakeCounter{n: 3}, "systemd (3 failed units)", InitSystem{Name: "systemd", FailedUnits: intPtr(3)}},
+		{"counter error", fakeCounter{err: errors.New("no systemctl")}, "systemd", InitSystem{Name: "systemd"}},
+	}
+	m, _ := Lookup("init")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetFailedUnitCounter(tt.counter)
+			value, err := m.Collect(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value.Text != tt.want {
+				t.Errorf("init = %q, want %q", value.Text, tt.want)
+			}
+			if !reflect.DeepEqual(value.Data, tt.wantData) {
+				t.Errorf("init data = %+v, want %+v", value.Data, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestInitModuleOnlyCountsSystemd(t *testing.T) {
+	proc := t.TempDir()
+	writeFiles(t, proc, map[string]string{"1/comm": "runit\n"})
+	setRoot(t, &procRoot, proc)
+	SetFailedUnitCounter(fakeCounter{n: 2})
+	t.Cleanup(func() { SetFailedUnitCounter(nil) })
+
+	m, _ := Lookup("init")
+	if value, err := m.Collect(context.Background()); err != nil || value.Text != "runit" {
+		t.Errorf("init = %q, %v, want %q", value.Text, err, "runit")
+	}
+}
+
+func TestCountFailedUnits(t *testing.T) {
+	tests := []struct {
+		out  string
+		want int
+	}{
+		{"", 0},
+		{"\n", 0},
+		{"nginx.service loaded failed failed A high performance web server\n", 1},
+		{"nginx.service loaded failed failed nginx\nsmartd.service loaded failed failed Self Monitoring\n\n", 2},
+	}
+	for _, tt := range tests {
+		if got := countFailedUnits(tt.out); got != tt.want {
+			t.Errorf("countFailedUnits(%q) = %d, want %d", tt.out, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package system
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// jsonResult is the JSON form of a Result.
+type jsonResult struct {
+	Name       string     `json:"name"`
+	Label      string     `json:"label"`
+	Text       string     `json:"text,omitempty"`
+	Data       any        `json:"data,omitempty"`
+	Items      []jsonItem `json:"items,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	TimedOut   bool       `json:"timed_out,omitempty"`
+	DurationMS float64    `json:"duration_ms"`
+}
+
+// jsonItem is the JSON form of an Item.
+type jsonItem struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+	Data any    `json:"data,omitempty"`
+}
+
+// MarshalJSON encodes the report with each module's text and numeric data,
+// for scripts that consume anifetch's output.
+func (r Report) MarshalJSON() ([]byte, error) {
+	results := make([]jsonResult, len(r.Results))
+	for i, res := range r.Results {
+		results[i] = jsonResult{
+			Name:       res.Name,
+			Label:      res.Label,
+			Text:       res.Value.Text,
+			Data:       res.Value.Data,
+			TimedOut:   errors.Is(res.Err, ErrTimeout),
+			DurationMS: float64(res.Duration.Microseconds()) / 1000,
+		}
+		for _, item := range res.Value.Items {
+			results[i].Items = append(results[i].Items, jsonItem(item))
+		}
+		if res.Err != nil {
+			results[i].Error = res.Err.Error()
+		}
+	}
+	return json.Marshal(struct {
+		Hostname   string       `json:"hostname"`
+		Modules    []jsonResult `json:"modules"`
+		DurationMS float64      `json:"duration_ms"`
+	}{r.Hostname, results, float64(r.Duration.Microseconds()) / 1000})
+}
@@ -0,0 +1,25 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package system
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+func kernelRelease() (string, error) {
+	return "", fmt.Errorf("kernel release not supported on %s", runtime.GOOS)
+}
+
+func kernelName() (string, string, error) {
+	return "", "", fmt.Errorf("kernel name not supported on %s", runtime.GOOS)
+}
+
+func machineArch() string {
+	return runtime.GOARCH
+}
+
+func systemUptime() (time.Duration, error) {
+	return 0, fmt.Errorf("uptime not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,37 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package system
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// kernelRelease returns the kernel release, as printed by uname -r.
+func kernelRelease() (string, error) {
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return "", err
+	}
+	return unix.ByteSliceToString(uts.Release[:]), nil
+}
+
+// kernelName returns the kernel name and release, e.g. "FreeBSD" and
+// "14.0-RELEASE".
+func kernelName() (string, string, error) {
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return "", "", err
+	}
+	return unix.ByteSliceToString(uts.Sysname[:]), unix.ByteSliceToString(uts.Release[:]), nil
+}
+
+// machineArch returns the hardware name, as printed by uname -m.
+func machineArch() string {
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return runtime.GOARCH
+	}
+	return unix.ByteSliceToString(uts.Machine[:])
+}
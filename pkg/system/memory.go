@@ -0,0 +1,116 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register(NewModule("memory", "Memory", func(ctx context.Context) (Value, error) {
+		mem, err := detectMemory()
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{Text: mem.RAM.String(), Data: mem.RAM}, nil
+	}))
+	Register(NewModule("swap", "Swap", func(ctx context.Context) (Value, error) {
+		mem, err := detectMemory()
+		if err != nil {
+			return Value{}, err
+		}
+		if mem.Swap.Total == 0 {
+			return Value{Text: "Disabled"}, nil
+		}
+		return Value{Text: mem.Swap.String(), Data: mem.Swap}, nil
+	}))
+}
+
+// Memory is RAM and swap usage in bytes.
+type Memory struct {
+	RAM  Usage
+	Swap Usage
+}
+
+// readMeminfo reads <root>/meminfo. Used memory is MemTotal minus
+// MemAvailable, which unlike MemFree counts reclaimable cache as free.
+func readMeminfo(root string) (Memory, error) {
+	f, err := os.Open(filepath.Join(root, "meminfo"))
+	if err != nil {
+		return Memory{}, fmt.Errorf("error reading meminfo: %v", err)
+	}
+	defer f.Close()
+
+	fields := map[string]uint64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		kb, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		if err == nil {
+			fields[key] = kb * 1024
+		}
+	}
+
+	total := fields["MemTotal"]
+	if total == 0 {
+		return Memory{}, fmt.Errorf("no MemTotal in %s/meminfo", root)
+	}
+	available, ok := fields["MemAvailable"]
+	if !ok {
+		// Kernels before 3.14
+		available = fields["MemFree"] + fields["Buffers"] + fields["Cached"]
+	}
+	return Memory{
+		RAM:  NewUsage(total-min(available, total), total),
+		Swap: NewUsage(fields["SwapTotal"]-min(fields["SwapFree"], fields["SwapTotal"]), fields["SwapTotal"]),
+	}, nil
+}
+
+// cgroupMemory returns the memory limit and usage of the cgroup v2 group
+// this process belongs to, with ok false when it is unlimited or not on
+// cgroup v2.
+func cgroupMemory(procRoot, sysRoot string) (Usage, bool) {
+	data, err := os.ReadFile(filepath.Join(procRoot, "self/cgroup"))
+	if err != nil {
+		return Usage{}, false
+	}
+	var group string
+	for _, line := range strings.Split(string(data), "\n") {
+		// The unified hierarchy is the "0::<path>" entry
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			group = path
+		}
+	}
+	if group == "" {
+		return Usage{}, false
+	}
+
+	dir := filepath.Join(sysRoot, "fs/cgroup", group)
+	limit, err := strconv.ParseUint(readSysfsString(filepath.Join(dir, "memory.max")), 10, 64)
+	if err != nil {
+		// "max" or missing
+		return Usage{}, false
+	}
+	current, err := strconv.ParseUint(readSysfsString(filepath.Join(dir, "memory.current")), 10, 64)
+	if err != nil {
+		return Usage{}, false
+	}
+	// Like MemAvailable, treat inactive page cache as free
+	if stat, err := os.ReadFile(filepath.Join(dir, "memory.stat")); err == nil {
+		for _, line := range strings.Split(string(stat), "\n") {
+			if v, ok := strings.CutPrefix(line, "inactive_file "); ok {
+				if n, err := strconv.ParseUint(v, 10, 64); err == nil && n < current {
+					current -= n
+				}
+			}
+		}
+	}
+	return NewUsage(current, limit), true
+}
@@ -0,0 +1,38 @@
+package system
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// detectMemory reads memory from sysctl. Free, speculative and purgeable
+// pages are counted as available, roughly matching Activity Monitor.
+func detectMemory() (Memory, error) {
+	total, err := unix.SysctlUint64("hw.memsize")
+	if err != nil {
+		return Memory{}, fmt.Errorf("error reading hw.memsize: %v", err)
+	}
+	pageSize, err := unix.SysctlUint32("vm.pagesize")
+	if err != nil {
+		return Memory{}, fmt.Errorf("error reading vm.pagesize: %v", err)
+	}
+
+	var pages uint64
+	for _, key := range []string{"vm.page_free_count", "vm.page_speculative_count", "vm.page_purgeable_count"} {
+		if n, err := unix.SysctlUint32(key); err == nil {
+			pages += uint64(n)
+		}
+	}
+	available := min(pages*uint64(pageSize), total)
+	mem := Memory{RAM: NewUsage(total-available, total)}
+
+	// struct xsw_usage { u_int64_t xsu_total, xsu_avail, xsu_used; ... }
+	if raw, err := unix.SysctlRaw("vm.swapusage"); err == nil && len(raw) >= 24 {
+		swapTotal := binary.LittleEndian.Uint64(raw[0:8])
+		swapUsed := binary.LittleEndian.Uint64(raw[16:24])
+		mem.Swap = NewUsage(swapUsed, swapTotal)
+	}
+	return mem, nil
+}
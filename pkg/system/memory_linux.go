@@ -0,0 +1,14 @@
+package system
+
+// detectMemory reads /proc/meminfo, reporting the cgroup limit instead of
+// the host's RAM when running in a memory-limited container.
+func detectMemory() (Memory, error) {
+	mem, err := readMeminfo(procRoot)
+	if err != nil {
+		return Memory{}, err
+	}
+	if limited, ok := cgroupMemory(procRoot, sysRoot); ok && limited.Total < mem.RAM.Total {
+		mem.RAM = limited
+	}
+	return mem, nil
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || dragonfly || netbsd || openbsd)
+
+package system
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func detectMemory() (Memory, error) {
+	return Memory{}, fmt.Errorf("memory not supported on %s", runtime.GOOS)
+}
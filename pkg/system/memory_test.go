@@ -0,0 +1,103 @@
+package system
+
+import "testing"
+
+func TestReadMeminfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		meminfo string
+		want    Memory
+	}{
+		{"MemAvailable", `MemTotal:       16000000 kB
+MemFree:         2000000 kB
+MemAvailable:    8000000 kB
+Buffers:          500000 kB
+Cached:          4000000 kB
+SwapTotal:       4000000 kB
+SwapFree:        3000000 kB
+`, Memory{
+			RAM:  NewUsage(8000000*1024, 16000000*1024),
+			Swap: NewUsage(1000000*1024, 4000000*1024),
+		}},
+		{"without MemAvailable", `MemTotal:       16000000 kB
+MemFree:         2000000 kB
+Buffers:          500000 kB
+Cached:          4000000 kB
+SwapTotal:             0 kB
+SwapFree:              0 kB
+`, Memory{
+			RAM: NewUsage(9500000*1024, 16000000*1024),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, map[string]string{"meminfo": tt.meminfo})
+			got, err := readMeminfo(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("readMeminfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMeminfoErrors(t *testing.T) {
+	if _, err := readMeminfo(t.TempDir()); err == nil {
+		t.Error("expected an error for a missing meminfo")
+	}
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"meminfo": "MemFree: 100 kB\n"})
+	if _, err := readMeminfo(root); err == nil {
+		t.Error("expected an error without MemTotal")
+	}
+}
+
+func TestCgroupMemory(t *testing.T) {
+	const group = "0::/system.slice/docker-abc.scope\n"
+	tests := []struct {
+		name   string
+		cgroup string
+		files  map[string]string
+		want   Usage
+		wantOK bool
+	}{
+		{"unlimited", group, map[string]string{
+			"system.slice/docker-abc.scope/memory.max":     "max\n",
+			"system.slice/docker-abc.scope/memory.current": "104857600\n",
+		}, Usage{}, false},
+		{"limit with inactive_file", group, map[string]string{
+			"system.slice/docker-abc.scope/memory.max":     "1073741824\n",
+			"system.slice/docker-abc.scope/memory.current": "536870912\n",
+			"system.slice/docker-abc.scope/memory.stat":    "anon 268435456\nfile 268435456\ninactive_file 134217728\nactive_file 134217728\n",
+		}, NewUsage(402653184, 1073741824), true},
+		{"limit without memory.stat", group, map[string]string{
+			"system.slice/docker-abc.scope/memory.max":     "1073741824\n",
+			"system.slice/docker-abc.scope/memory.current": "536870912\n",
+		}, NewUsage(536870912, 1073741824), true},
+		{"root cgroup", "0::/\n", map[string]string{
+			"memory.stat": "anon 268435456\n",
+		}, Usage{}, false},
+		{"cgroup v1", "12:memory:/docker/abc\n1:name=systemd:/docker/abc\n", map[string]string{
+			"memory.max": "1073741824\n",
+		}, Usage{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc, sys := t.TempDir(), t.TempDir()
+			writeFiles(t, proc, map[string]string{"self/cgroup": tt.cgroup})
+			files := map[string]string{}
+			for name, content := range tt.files {
+				files["fs/cgroup/"+name] = content
+			}
+			writeFiles(t, sys, files)
+
+			got, ok := cgroupMemory(proc, sys)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("cgroupMemory() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
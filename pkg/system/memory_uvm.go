@@ -0,0 +1,24 @@
+//go:build netbsd || openbsd
+
+package system
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// detectMemory reads the uvmexp statistics, which cover RAM and swap.
+func detectMemory() (Memory, error) {
+	uvm, err := unix.SysctlUvmexp("vm.uvmexp")
+	if err != nil {
+		return Memory{}, fmt.Errorf("error reading vm.uvmexp: %v", err)
+	}
+	page := uint64(uvm.Pagesize)
+	total := uint64(uvm.Npages) * page
+	available := min(uint64(uvm.Free+uvm.Inactive)*page, total)
+	return Memory{
+		RAM:  NewUsage(total-available, total),
+		Swap: NewUsage(uint64(uvm.Swpginuse)*page, uint64(uvm.Swpages)*page),
+	}, nil
+}
@@ -0,0 +1,31 @@
+//go:build freebsd || dragonfly
+
+package system
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// detectMemory reads the vm.stats counters; inactive and cached pages are
+// counted as available. Swap usage needs kvm and is not reported.
+func detectMemory() (Memory, error) {
+	total, err := unix.SysctlUint64("hw.physmem")
+	if err != nil {
+		return Memory{}, fmt.Errorf("error reading hw.physmem: %v", err)
+	}
+	pageSize, err := unix.SysctlUint32("vm.stats.vm.v_page_size")
+	if err != nil {
+		return Memory{}, fmt.Errorf("error reading page size: %v", err)
+	}
+
+	var pages uint64
+	for _, key := range []string{"vm.stats.vm.v_free_count", "vm.stats.vm.v_inactive_count", "vm.stats.vm.v_cache_count"} {
+		if n, err := unix.SysctlUint32(key); err == nil {
+			pages += uint64(n)
+		}
+	}
+	available := min(pages*uint64(pageSize), total)
+	return Memory{RAM: NewUsage(total-available, total)}, nil
+}
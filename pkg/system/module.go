@@ -0,0 +1,223 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Value is what a module reports: Text for display, and optionally Data
+// with the same information in numeric form for JSON output. Modules that
+// find several things, such as one line per mounted disk, report Items
+// instead.
+type Value struct {
+	Text  string
+	Data  any
+	Items []Item
+}
+
+// Item is one of several entries of a module, shown on its own line with
+// Name after the module's label, e.g. "Disk (/home)".
+type Item struct {
+	Name string
+	Text string
+	Data any
+}
+
+// Module collects one piece of system information.
+type Module interface {
+	// Name is the identifier used in configuration, e.g. "cpu".
+	Name() string
+	// Collect gathers the value. An empty Text means there is nothing
+	// to show, such as a battery module on a desktop.
+	Collect(ctx context.Context) (Value, error)
+}
+
+// Labeler is implemented by modules that want a display label other than
+// their name.
+type Labeler interface {
+	Label() string
+}
+
+// FuncModule adapts a function to the Module interface.
+type FuncModule struct {
+	name    string
+	label   string
+	collect func(ctx context.Context) (Value, error)
+}
+
+// NewModule returns a module called name, displayed as label, that runs
+// collect.
+func NewModule(name, label string, collect func(ctx context.Context) (Value, error)) *FuncModule {
+	return &FuncModule{name: name, label: label, collect: collect}
+}
+
+func (m *FuncModule) Name() string  { return m.name }
+func (m *FuncModule) Label() string { return m.label }
+
+func (m *FuncModule) Collect(ctx context.Context) (Value, error) {
+	return m.collect(ctx)
+}
+
+var registry = map[string]Module{}
+
+// Register makes a module available by name. Registering the same name
+// twice replaces the earlier module.
+func Register(m Module) {
+	registry[m.Name()] = m
+}
+
+// Lookup returns the registered module called name.
+func Lookup(name string) (Module, bool) {
+	m, ok := registry[name]
+	return m, ok
+}
+
+// ModuleNames lists every registered module, sorted.
+func ModuleNames() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultModules is the module list used when the configuration has none.
+var DefaultModules = []string{"os", "host", "kernel", "uptime", "packages", "shell", "resolution", "de", "wm", "terminal", "cpu", "gpu", "memory", "disk", "battery"}
+
+// LabelOf returns the display label of a module.
+func LabelOf(m Module) string {
+	if l, ok := m.(Labeler); ok {
+		return l.Label()
+	}
+	return m.Name()
+}
+
+// ErrTimeout is the error of a module that did not finish in time.
+var ErrTimeout = errors.New("timed out")
+
+// Result is the outcome of running one module.
+type Result struct {
+	Name     string
+	Label    string
+	Value    Value
+	Err      error
+	Duration time.Duration
+}
+
+// Report is everything collected for one run.
+type Report struct {
+	Hostname string
+	Results  []Result
+	Duration time.Duration
+}
+
+// Text returns the value of the named module, or "" if it was not run or
+// failed.
+func (r Report) Text(name string) string {
+	for _, res := range r.Results {
+		if res.Name == name && res.Err == nil {
+			return res.Value.Text
+		}
+	}
+	return ""
+}
+
+// Data returns the data of the named module, or nil if it was not run or
+// failed.
+func (r Report) Data(name string) any {
+	for _, res := range r.Results {
+		if res.Name == name && res.Err == nil {
+			return res.Value.Data
+		}
+	}
+	return nil
+}
+
+// Options controls how modules are collected.
+type Options struct {
+	// Labels overrides the display label of individual modules
+	Labels map[string]string
+	// ModuleTimeout bounds each module; zero means no limit
+	ModuleTimeout time.Duration
+	// Timeout bounds the whole collection; zero means no limit
+	Timeout time.Duration
+}
+
+// Collect runs the named modules concurrently and returns their results in
+// the order given. Modules still running when their own timeout or the
+// overall deadline passes are reported with ErrTimeout, so a slow module
+// never holds up the rest.
+func Collect(ctx context.Context, names []string, opts Options) Report {
+	start := time.Now()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	report := Report{Results: make([]Result, len(names))}
+	if hostname, err := os.Hostname(); err == nil {
+		report.Hostname = hostname
+	}
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			report.Results[i] = collectOne(ctx, name, opts)
+		}(i, name)
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	return report
+}
+
+// collectOne runs a single module, giving up once its time is up even if
+// the module ignores cancellation.
+func collectOne(ctx context.Context, name string, opts Options) Result {
+	res := Result{Name: name, Label: name}
+	m, ok := Lookup(name)
+	if !ok {
+		res.Err = fmt.Errorf("unknown module %q", name)
+		return res
+	}
+
+	res.Label = LabelOf(m)
+	if label, ok := opts.Labels[name]; ok {
+		res.Label = label
+	}
+
+	if opts.ModuleTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.ModuleTimeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		value Value
+		err   error
+	}
+	start := time.Now()
+	ch := make(chan outcome, 1)
+	go func() {
+		value, err := m.Collect(ctx)
+		ch <- outcome{value, err}
+	}()
+
+	select {
+	case out := <-ch:
+		res.Value, res.Err = out.value, out.err
+	case <-ctx.Done():
+		res.Err = ErrTimeout
+	}
+	res.Duration = time.Since(start)
+	return res
+}
@@ -0,0 +1,204 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(NewModule("network", "Network", func(ctx context.Context) (Value, error) {
+		ifaces, err := activeInterfaces(defaultRouteInterface(procRoot))
+		if err != nil {
+			return Value{}, err
+		}
+		var value Value
+		for _, iface := range ifaces {
+			value.Items = append(value.Items, Item{Name: iface.Name, Text: iface.String(), Data: iface})
+		}
+		return value, nil
+	}))
+	Register(NewModule("publicip", "Public IP", func(ctx context.Context) (Value, error) {
+		if publicIPResolver == nil {
+			return Value{}, nil
+		}
+		ip, err := publicIPResolver.PublicIP(ctx)
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{Text: ip}, nil
+	}))
+}
+
+// Interface is a network interface that is up and has addresses.
+type Interface struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+	// Default marks the interface of the default route
+	Default bool `json:"default"`
+}
+
+// String formats the interface like "192.168.1.20/24, 2001:db8::20/64".
+func (i Interface) String() string {
+	s := strings.Join(i.Addresses, ", ")
+	if i.Default {
+		s += " (default route)"
+	}
+	return s
+}
+
+// activeInterfaces lists interfaces that are up, leaving out loopback and
+// link-local addresses. The default route's interface comes first.
+func activeInterfaces(defaultIface string) ([]Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("error listing interfaces: %v", err)
+	}
+
+	var active []Interface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		info := Interface{Name: iface.Name, Default: iface.Name == defaultIface}
+		for _, addr := range addrs {
+			ipnet, ok := addr.(*net.IPNet)
+			if !ok || ipnet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			info.Addresses = append(info.Addresses, ipnet.String())
+		}
+		if len(info.Addresses) == 0 {
+			continue
+		}
+		if info.Default {
+			active = append([]Interface{info}, active...)
+		} else {
+			active = append(active, info)
+		}
+	}
+	return active, nil
+}
+
+// defaultRouteInterface returns the interface of the IPv4 default route
+// from <root>/net/route, or "" if there is none.
+func defaultRouteInterface(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "net/route"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n")[1:] {
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask ...
+		fields := strings.Fields(line)
+		if len(fields) >= 8 && fields[1] == "00000000" && fields[7] == "00000000" {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// IPResolver looks up this machine's public IP address.
+type IPResolver interface {
+	PublicIP(ctx context.Context) (string, error)
+}
+
+// publicIPResolver backs the publicip module; nil disables it.
+var publicIPResolver IPResolver
+
+// SetPublicIPResolver sets how the publicip module finds the address.
+func SetPublicIPResolver(r IPResolver) {
+	publicIPResolver = r
+}
+
+// HTTPResolver asks a web service, such as https://api.ipify.org, that
+// answers with the caller's address as plain text.
+type HTTPResolver struct {
+	URL     string
+	Timeout time.Duration
+	Client  *http.Client
+}
+
+// NewHTTPResolver returns a resolver for url that gives up after timeout.
+func NewHTTPResolver(url string, timeout time.Duration) *HTTPResolver {
+	return &HTTPResolver{URL: url, Timeout: timeout, Client: http.DefaultClient}
+}
+
+func (r *HTTPResolver) PublicIP(ctx context.Context) (string, error) {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", r.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %v", err)
+	}
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error looking up public IP: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error looking up public IP: %s", resp.Status)
+	}
+
+	// An address is at most 45 characters; don't read a whole web page
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64))
+	if err != nil {
+		return "", fmt.Errorf("error reading public IP: %v", err)
+	}
+	ip := net.ParseIP(strings.TrimSpace(string(body)))
+	if ip == nil {
+		return "", fmt.Errorf("%s did not return an IP address", r.URL)
+	}
+	return ip.String(), nil
+}
+
+// CachingResolver remembers another resolver's answer in a file for TTL,
+// so the lookup only costs time once in a while.
+type CachingResolver struct {
+	Resolver IPResolver
+	Path     string
+	TTL      time.Duration
+}
+
+// NewCachingResolver caches r's answers in path for ttl.
+func NewCachingResolver(r IPResolver, path string, ttl time.Duration) *CachingResolver {
+	return &CachingResolver{Resolver: r, Path: path, TTL: ttl}
+}
+
+// publicIPCache is the layout of the cache file.
+type publicIPCache struct {
+	IP      string    `json:"ip"`
+	Fetched time.Time `json:"fetched"`
+}
+
+func (c *CachingResolver) PublicIP(ctx context.Context) (string, error) {
+	var cached publicIPCache
+	if data, err := os.ReadFile(c.Path); err == nil && json.Unmarshal(data, &cached) == nil {
+		if cached.IP != "" && time.Since(cached.Fetched) < c.TTL {
+			return cached.IP, nil
+		}
+	}
+
+	ip, err := c.Resolver.PublicIP(ctx)
+	if err != nil {
+		return "", err
+	}
+	if data, err := json.Marshal(publicIPCache{IP: ip, Fetched: time.Now()}); err == nil {
+		os.WriteFile(c.Path, data, 0644)
+	}
+	return ip, nil
+}
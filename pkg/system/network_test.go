@@ -0,0 +1,144 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHTTPResolver(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{"IPv4", http.StatusOK, "203.0.113.7\n", "203.0.113.7", false},
+		{"IPv6", http.StatusOK, "2001:DB8::1", "2001:db8::1", false},
+		{"not found", http.StatusNotFound, "203.0.113.7", "", true},
+		{"server error", http.StatusServiceUnavailable, "", "", true},
+		{"not an IP", http.StatusOK, "<html>rate limited</html>", "", true},
+		{"empty", http.StatusOK, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			got, err := NewHTTPResolver(srv.URL, time.Second).PublicIP(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PublicIP() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PublicIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPResolverTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		w.Write([]byte("203.0.113.7"))
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	if _, err := NewHTTPResolver(srv.URL, 50*time.Millisecond).PublicIP(context.Background()); err == nil {
+		t.Error("expected an error from a server slower than the timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("PublicIP() took %v despite a 50ms timeout", elapsed)
+	}
+}
+
+// fakeResolver returns ip or err and counts the lookups.
+type fakeResolver struct {
+	ip    string
+	err   error
+	calls int
+}
+
+func (r *fakeResolver) PublicIP(ctx context.Context) (string, error) {
+	r.calls++
+	return r.ip, r.err
+}
+
+func writeIPCache(t *testing.T, path, ip string, fetched time.Time) {
+	t.Helper()
+	data, err := json.Marshal(publicIPCache{IP: ip, Fetched: fetched})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCachingResolver(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fetches and caches", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "publicip.json")
+		fake := &fakeResolver{ip: "203.0.113.7"}
+		c := NewCachingResolver(fake, path, time.Hour)
+		for i := 0; i < 2; i++ {
+			if ip, err := c.PublicIP(ctx); err != nil || ip != "203.0.113.7" {
+				t.Fatalf("PublicIP() = %q, %v", ip, err)
+			}
+		}
+		if fake.calls != 1 {
+			t.Errorf("resolver called %d times, want 1", fake.calls)
+		}
+	})
+
+	t.Run("serves within TTL", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "publicip.json")
+		writeIPCache(t, path, "198.51.100.1", time.Now().Add(-10*time.Minute))
+		fake := &fakeResolver{ip: "203.0.113.7"}
+		ip, err := NewCachingResolver(fake, path, time.Hour).PublicIP(ctx)
+		if err != nil || ip != "198.51.100.1" || fake.calls != 0 {
+			t.Errorf("PublicIP() = %q, %v after %d lookups, want the cached address", ip, err, fake.calls)
+		}
+	})
+
+	t.Run("refetches after expiry", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "publicip.json")
+		writeIPCache(t, path, "198.51.100.1", time.Now().Add(-2*time.Hour))
+		fake := &fakeResolver{ip: "203.0.113.7"}
+		ip, err := NewCachingResolver(fake, path, time.Hour).PublicIP(ctx)
+		if err != nil || ip != "203.0.113.7" || fake.calls != 1 {
+			t.Errorf("PublicIP() = %q, %v after %d lookups, want a fresh address", ip, err, fake.calls)
+		}
+
+		var cached publicIPCache
+		data, _ := os.ReadFile(path)
+		if err := json.Unmarshal(data, &cached); err != nil || cached.IP != "203.0.113.7" {
+			t.Errorf("cache file = %s, want the fresh address", data)
+		}
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "publicip.json")
+		fake := &fakeResolver{err: errors.New("offline")}
+		if _, err := NewCachingResolver(fake, path, time.Hour).PublicIP(ctx); err == nil {
+			t.Error("expected the resolver's error")
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("cache file written after an error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(NewModule("packages", "Packages", func(ctx context.Context) (Value, error) {
+		var parts []string
+		for i, n := range countPackages(ctx) {
+			if n > 0 {
+				parts = append(parts, fmt.Sprintf("%d (%s)", n, packageManagers[i].name))
+			}
+		}
+		return Value{Text: strings.Join(parts, ", ")}, nil
+	}))
+}
+
+// partialResultMargin is how long before its deadline the packages module
+// stops waiting, so the counts that finished are reported instead of the
+// whole module timing out.
+const partialResultMargin = 50 * time.Millisecond
+
+// countPackages runs every package manager's counter concurrently and
+// returns the counts in packageManagers order. Counters that fail, or
+// are still running shortly before ctx's deadline, count as zero.
+func countPackages(ctx context.Context) []int {
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline.Add(-partialResultMargin))
+		defer cancel()
+	}
+
+	type result struct{ i, n int }
+	// Buffered so that counters finishing after we stop waiting don't block
+	results := make(chan result, len(packageManagers))
+	for i, pm := range packageManagers {
+		go func(i int, count func(context.Context) (int, error)) {
+			n, err := count(ctx)
+			if err != nil {
+				n = 0
+			}
+			results <- result{i, n}
+		}(i, pm.count)
+	}
+
+	counts := make([]int, len(packageManagers))
+	for range packageManagers {
+		select {
+		case r := <-results:
+			counts[r.i] = r.n
+		case <-ctx.Done():
+			return counts
+		}
+	}
+	return counts
+}
+
+// packageManager counts the packages installed by one package manager.
+type packageManager struct {
+	name  string
+	count func(ctx context.Context) (int, error)
+}
+
+// packageManagers are all counted; most read their database directly so
+// no process has to be spawned.
+var packageManagers = []packageManager{
+	{"dpkg", func(ctx context.Context) (int, error) {
+		return countDpkg(filepath.Join(rootDir, "var/lib/dpkg/status"))
+	}},
+	{"pacman", func(ctx context.Context) (int, error) {
+		return countDirs(filepath.Join(rootDir, "var/lib/pacman/local"))
+	}},
+	{"rpm", countRpm},
+	{"apk", func(ctx context.Context) (int, error) {
+		return countApk(filepath.Join(rootDir, "lib/apk/db/installed"))
+	}},
+	{"nix", countNix},
+	{"brew", func(ctx context.Context) (int, error) {
+		return countFirst(brewPrefixes(), "Cellar")
+	}},
+	{"brew-cask", func(ctx context.Context) (int, error) {
+		return countFirst(brewPrefixes(), "Caskroom")
+	}},
+	{"flatpak", func(ctx context.Context) (int, error) {
+		return countFlatpak(flatpakInstallations())
+	}},
+	{"snap", func(ctx context.Context) (int, error) {
+		return countSnap(filepath.Join(rootDir, "snap"))
+	}},
+}
+
+// countDpkg counts packages whose status in a dpkg status file is
+// "installed", skipping removed packages that only left config files.
+func countDpkg(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return parseDpkgStatus(file)
+}
+
+func parseDpkgStatus(r io.Reader) (int, error) {
+	count := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "Status: ") {
+			continue
+		}
+		// Status is "want flag status", e.g. "install ok installed"
+		fields := strings.Fields(strings.TrimPrefix(line, "Status: "))
+		if len(fields) == 3 && fields[2] == "installed" {
+			count++
+		}
+	}
+	return count, scanner.Err()
+}
+
+// countApk counts the package records ("P:" lines) of an apk database.
+func countApk(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "P:") {
+			count++
+		}
+	}
+	return count, scanner.Err()
+}
+
+// countDirs counts the subdirectories of dir, which is how pacman's local
+// database and Homebrew's Cellar store one package each.
+func countDirs(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, e := range entries {
+		if e.IsDir() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// countFirst counts subdirectories of sub in the first prefix that has it.
+func countFirst(prefixes []string, sub string) (int, error) {
+	for _, prefix := range prefixes {
+		if n, err := countDirs(filepath.Join(prefix, sub)); err == nil {
+			return n, nil
+		}
+	}
+	return 0, os.ErrNotExist
+}
+
+func brewPrefixes() []string {
+	prefixes := []string{}
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		prefixes = append(prefixes, prefix)
+	}
+	for _, prefix := range []string{"opt/homebrew", "usr/local", "home/linuxbrew/.linuxbrew"} {
+		prefixes = append(prefixes, filepath.Join(rootDir, prefix))
+	}
+	return prefixes
+}
+
+func flatpakInstallations() []string {
+	dirs := []string{filepath.Join(rootDir, "var/lib/flatpak")}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".local/share/flatpak"))
+	}
+	return dirs
+}
+
+// countFlatpak counts installed apps and runtimes across installations.
+// Runtimes are stored as <name>/<arch>/<branch>, so each branch counts.
+func countFlatpak(installations []string) (int, error) {
+	count := 0
+	found := false
+	for _, dir := range installations {
+		if n, err := countDirs(filepath.Join(dir, "app")); err == nil {
+			count += n
+			found = true
+		}
+		branches, _ := filepath.Glob(filepath.Join(dir, "runtime", "*", "*", "*"))
+		count += len(branches)
+	}
+	if !found && count == 0 {
+		return 0, os.ErrNotExist
+	}
+	return count, nil
+}
+
+// countSnap counts mounted snaps, skipping the bin directory and README.
+func countSnap(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != "bin" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// countRpm falls back to rpm itself since the rpmdb is a binary database.
+func countRpm(ctx context.Context) (int, error) {
+	if _, err := os.Stat(filepath.Join(rootDir, "var/lib/rpm")); err != nil {
+		return 0, err
+	}
+	out, err := exec.CommandContext(ctx, "rpm", "-qa").Output()
+	if err != nil {
+		return 0, err
+	}
+	return countLines(string(out)), nil
+}
+
+// countNix counts packages in the user and default profiles from their
+// manifests, and the NixOS system closure via nix-store.
+func countNix(ctx context.Context) (int, error) {
+	count := 0
+	found := false
+
+	profiles := []string{filepath.Join(rootDir, "nix/var/nix/profiles/default")}
+	if home, err := os.UserHomeDir(); err == nil {
+		profiles = append(profiles, filepath.Join(home, ".nix-profile"))
+	}
+	for _, profile := range profiles {
+		if n, err := countNixManifest(filepath.Join(profile, "manifest.json")); err == nil {
+			count += n
+			found = true
+		}
+	}
+
+	system := filepath.Join(rootDir, "run/current-system/sw")
+	if _, err := os.Stat(system); err == nil {
+		if out, err := exec.CommandContext(ctx, "nix-store", "-qR", system).Output(); err == nil {
+			count += countLines(string(out))
+			found = true
+		}
+	}
+
+	if !found {
+		return 0, os.ErrNotExist
+	}
+	return count, nil
+}
+
+// countNixManifest counts the elements of a `nix profile` manifest, which
+// is a list in version 1 and 2 and an object keyed by name from version 3.
+func countNixManifest(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var manifest struct {
+		Elements json.RawMessage `json:"elements"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return 0, err
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(manifest.Elements, &list); err == nil {
+		return len(list), nil
+	}
+	var named map[string]json.RawMessage
+	if err := json.Unmarshal(manifest.Elements, &named); err != nil {
+		return 0, err
+	}
+	return len(named), nil
+}
+
+func countLines(text string) int {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0
+	}
+	return strings.Count(text, "\n") + 1
+}
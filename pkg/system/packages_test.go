@@ -0,0 +1,115 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const packagesRoot = "testdata/packages"
+
+func TestParseDpkgStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   int
+	}{
+		{"installed", "Package: a\nStatus: install ok installed\n", 1},
+		{"config files only", "Package: a\nStatus: deinstall ok config-files\n", 0},
+		{"held", "Package: a\nStatus: hold ok installed\n", 1},
+		{"not installed", "Package: a\nStatus: purge ok not-installed\n", 0},
+		{"half installed", "Package: a\nStatus: install reinstreq half-installed\n", 0},
+		{"empty", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDpkgStatus(strings.NewReader(tt.status))
+			if err != nil || got != tt.want {
+				t.Errorf("parseDpkgStatus() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackageCounters(t *testing.T) {
+	tests := []struct {
+		name  string
+		count func() (int, error)
+		want  int
+	}{
+		{"dpkg", func() (int, error) { return countDpkg(filepath.Join(packagesRoot, "var/lib/dpkg/status")) }, 2},
+		{"apk", func() (int, error) { return countApk(filepath.Join(packagesRoot, "lib/apk/db/installed")) }, 2},
+		{"pacman", func() (int, error) { return countDirs(filepath.Join(packagesRoot, "var/lib/pacman/local")) }, 3},
+		{"snap", func() (int, error) { return countSnap(filepath.Join(packagesRoot, "snap")) }, 3},
+		// Two apps plus three runtime branches
+		{"flatpak", func() (int, error) {
+			return countFlatpak([]string{filepath.Join(packagesRoot, "var/lib/flatpak"), "testdata/missing"})
+		}, 5},
+		{"nix v1", func() (int, error) { return countNixManifest("testdata/nix/manifest-v1.json") }, 2},
+		{"nix v2", func() (int, error) { return countNixManifest("testdata/nix/manifest-v2.json") }, 1},
+		{"nix v3", func() (int, error) { return countNixManifest("testdata/nix/manifest-v3.json") }, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.count()
+			if err != nil || got != tt.want {
+				t.Errorf("count = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackageCounterErrors(t *testing.T) {
+	if _, err := countNixManifest("testdata/nix/manifest-bad.json"); err == nil {
+		t.Error("countNixManifest accepted a string elements field")
+	}
+	if _, err := countFlatpak([]string{"testdata/missing"}); err == nil {
+		t.Error("countFlatpak found packages without an installation")
+	}
+	if _, err := countDpkg("testdata/missing/status"); err == nil {
+		t.Error("countDpkg succeeded without a status file")
+	}
+}
+
+func TestPackagesModule(t *testing.T) {
+	setRoot(t, &rootDir, packagesRoot)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("HOMEBREW_PREFIX", "")
+
+	m, _ := Lookup("packages")
+	value, err := m.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "2 (dpkg), 3 (pacman), 2 (apk), 1 (nix), 5 (flatpak), 3 (snap)"
+	if value.Text != want {
+		t.Errorf("packages = %q, want %q", value.Text, want)
+	}
+}
+
+func TestCountPackagesReturnsFinishedCounts(t *testing.T) {
+	old := packageManagers
+	t.Cleanup(func() { packageManagers = old })
+	packageManagers = []packageManager{
+		{"fast", func(ctx context.Context) (int, error) { return 7, nil }},
+		{"slow", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}},
+		{"broken", func(ctx context.Context) (int, error) { return 3, errors.New("broken") }},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	counts := countPackages(ctx)
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Errorf("countPackages waited %v, past the deadline", elapsed)
+	}
+	if counts[0] != 7 || counts[1] != 0 || counts[2] != 0 {
+		t.Errorf("countPackages() = %v, want [7 0 0]", counts)
+	}
+}
@@ -0,0 +1,80 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procRoot is where procfs is mounted. It is a variable so collectors can
+// be pointed at a fixture tree.
+var procRoot = "/proc"
+
+// readProcUptime parses the first field of <root>/uptime, the seconds
+// since boot.
+func readProcUptime(root string) (time.Duration, error) {
+	data, err := os.ReadFile(filepath.Join(root, "uptime"))
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty %s/uptime", root)
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing %s/uptime: %v", root, err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// procInfo is the part of /proc/<pid>/stat needed to walk the process tree.
+type procInfo struct {
+	PID  int
+	Comm string
+	PPID int
+}
+
+// readProcStat parses <root>/<pid>/stat. The command name is enclosed in
+// parentheses and may itself contain spaces and parentheses, so fields are
+// counted from the last ')'.
+func readProcStat(root string, pid int) (procInfo, error) {
+	data, err := os.ReadFile(filepath.Join(root, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procInfo{}, err
+	}
+	stat := string(data)
+	open := strings.IndexByte(stat, '(')
+	end := strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return procInfo{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return procInfo{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return procInfo{}, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	return procInfo{PID: pid, Comm: stat[open+1 : end], PPID: ppid}, nil
+}
+
+// processAncestors returns pid's ancestors, nearest first, stopping before
+// init.
+func processAncestors(root string, pid int) []procInfo {
+	var chain []procInfo
+	for depth := 0; pid > 1 && depth < 64; depth++ {
+		info, err := readProcStat(root, pid)
+		if err != nil {
+			break
+		}
+		chain = append(chain, info)
+		pid = info.PPID
+	}
+	return chain
+}
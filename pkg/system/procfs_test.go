@@ -0,0 +1,42 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadProcUptime(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"valid", "12345.67 54321.00\n", 12345*time.Second + 670*time.Millisecond, false},
+		{"empty", "", 0, true},
+		{"malformed", "abc 1.0\n", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, "uptime"), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readProcUptime(root)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readProcUptime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Round(time.Millisecond) != tt.want {
+				t.Errorf("readProcUptime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadProcUptimeMissing(t *testing.T) {
+	if _, err := readProcUptime(t.TempDir()); err == nil {
+		t.Error("expected an error for a missing uptime file")
+	}
+}
@@ -0,0 +1,181 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(NewModule("resolution", "Resolution", func(ctx context.Context) (Value, error) {
+		if isHeadless() || inSSHSession() {
+			return Value{}, nil
+		}
+		monitors := detectMonitors(ctx)
+		if len(monitors) == 1 {
+			return Value{Text: monitors[0].String(), Data: monitors[0]}, nil
+		}
+		var value Value
+		for _, m := range monitors {
+			value.Items = append(value.Items, Item{Name: m.Name, Text: m.String(), Data: m})
+		}
+		return value, nil
+	}))
+}
+
+// Monitor is the current mode of one connected display.
+type Monitor struct {
+	Name    string  `json:"name"`
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+	Refresh float64 `json:"refresh_hz,omitempty"` // zero when unknown
+}
+
+// String formats the monitor like "2560x1440 @ 144Hz".
+func (m Monitor) String() string {
+	s := fmt.Sprintf("%dx%d", m.Width, m.Height)
+	if m.Refresh > 0 {
+		s += fmt.Sprintf(" @ %.0fHz", m.Refresh)
+	}
+	return s
+}
+
+// inSSHSession reports a login over SSH. With X forwarding DISPLAY is set,
+// but the display belongs to the client machine.
+func inSSHSession() bool {
+	return os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != ""
+}
+
+// resolutionToolTimeout bounds each external tool.
+const resolutionToolTimeout = 300 * time.Millisecond
+
+// detectMonitors asks the display server through wlr-randr or xrandr,
+// which know the refresh rate, and otherwise falls back to the preferred
+// modes of connected DRM connectors.
+func detectMonitors(ctx context.Context) []Monitor {
+	if displayServer() == "Wayland" {
+		if out, err := runTool(ctx, "wlr-randr"); err == nil {
+			if monitors := parseWlrRandr(out); len(monitors) > 0 {
+				return monitors
+			}
+		}
+	}
+	if os.Getenv("DISPLAY") != "" {
+		if out, err := runTool(ctx, "xrandr", "--current"); err == nil {
+			if monitors := parseXrandr(out); len(monitors) > 0 {
+				return monitors
+			}
+		}
+	}
+	return drmMonitors(sysRoot)
+}
+
+func runTool(ctx context.Context, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, resolutionToolTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	return string(out), err
+}
+
+var (
+	modeSize     = regexp.MustCompile(`^([0-9]+)x([0-9]+)`)
+	xrandrOutput = regexp.MustCompile(`^(\S+) connected`)
+)
+
+// parseXrandr reads "xrandr --current", where the current mode of each
+// connected output is the rate marked with "*":
+//
+//	HDMI-1 connected primary 2560x1440+0+0 ...
+//	   2560x1440     59.95*+  144.00
+func parseXrandr(out string) []Monitor {
+	var monitors []Monitor
+	name := ""
+	for _, line := range strings.Split(out, "\n") {
+		if m := xrandrOutput.FindStringSubmatch(line); m != nil {
+			name = m[1]
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			name = ""
+		}
+		fields := strings.Fields(line)
+		if name == "" || len(fields) < 2 {
+			continue
+		}
+		size := modeSize.FindStringSubmatch(fields[0])
+		if size == nil {
+			continue
+		}
+		for _, rate := range fields[1:] {
+			if !strings.Contains(rate, "*") {
+				continue
+			}
+			hz, _ := strconv.ParseFloat(strings.Trim(rate, "*+"), 64)
+			width, _ := strconv.Atoi(size[1])
+			height, _ := strconv.Atoi(size[2])
+			monitors = append(monitors, Monitor{Name: name, Width: width, Height: height, Refresh: hz})
+			name = ""
+			break
+		}
+	}
+	return monitors
+}
+
+// parseWlrRandr reads wlr-randr's output, where outputs start in the first
+// column and the current mode is marked:
+//
+//	DP-1 "Dell Inc. DELL U2720Q"
+//	  Enabled: yes
+//	  Modes:
+//	    3840x2160 px, 59.997002 Hz (preferred, current)
+func parseWlrRandr(out string) []Monitor {
+	var monitors []Monitor
+	name := ""
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" && line[0] != ' ' {
+			name, _, _ = strings.Cut(line, " ")
+			continue
+		}
+		if name == "" || !strings.Contains(line, "current") {
+			continue
+		}
+		var m Monitor
+		if _, err := fmt.Sscanf(strings.TrimSpace(line), "%dx%d px, %f Hz", &m.Width, &m.Height, &m.Refresh); err != nil {
+			continue
+		}
+		m.Name = name
+		monitors = append(monitors, m)
+		name = ""
+	}
+	return monitors
+}
+
+// drmMonitors lists connected connectors under <root>/class/drm with the
+// first mode of each, which is the panel's preferred one. sysfs does not
+// expose refresh rates.
+func drmMonitors(root string) []Monitor {
+	dirs, _ := filepath.Glob(filepath.Join(root, "class/drm/card*-*"))
+	var monitors []Monitor
+	for _, dir := range dirs {
+		if readSysfsString(filepath.Join(dir, "status")) != "connected" {
+			continue
+		}
+		mode, _, _ := strings.Cut(readSysfsString(filepath.Join(dir, "modes")), "\n")
+		size := modeSize.FindStringSubmatch(mode)
+		if size == nil {
+			continue
+		}
+		width, _ := strconv.Atoi(size[1])
+		height, _ := strconv.Atoi(size[2])
+		// "card0-HDMI-A-1" is connector HDMI-A-1 of card0
+		_, name, _ := strings.Cut(filepath.Base(dir), "-")
+		monitors = append(monitors, Monitor{Name: name, Width: width, Height: height})
+	}
+	return monitors
+}
@@ -0,0 +1,100 @@
+package system
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseXrandr(t *testing.T) {
+	out := `Screen 0: minimum 320 x 200, current 4480 x 1440, maximum 16384 x 16384
+eDP-1 connected primary 1920x1080+2560+360 (normal left inverted right x axis y axis) 309mm x 174mm
+   1920x1080     60.02*+  59.93
+   1680x1050     59.88
+HDMI-1 disconnected (normal left inverted right x axis y axis)
+   1920x1080     60.00
+DP-1 connected 2560x1440+0+0 (normal left inverted right x axis y axis) 597mm x 336mm
+   2560x1440     59.95 +  143.97*
+   1920x1080     60.00
+DP-2 connected (normal left inverted right x axis y axis)
+   1920x1080     60.00 +
+`
+	want := []Monitor{
+		{Name: "eDP-1", Width: 1920, Height: 1080, Refresh: 60.02},
+		{Name: "DP-1", Width: 2560, Height: 1440, Refresh: 143.97},
+	}
+	if got := parseXrandr(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseXrandr() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseWlrRandr(t *testing.T) {
+	out := `DP-1 "Dell Inc. DELL U2720Q ABC123 (DP-1)"
+  Make: Dell Inc.
+  Enabled: yes
+  Modes:
+    3840x2160 px, 29.981001 Hz
+    3840x2160 px, 59.997002 Hz (preferred, current)
+  Position: 0,0
+eDP-1 "Sharp Corporation 0x14F9 (eDP-1)"
+  Enabled: no
+  Modes:
+    2256x1504 px, 59.999001 Hz (preferred)
+HDMI-A-1 "Unknown"
+  Enabled: yes
+  Modes:
+    1920x1080 px, 60.000000 Hz (current)
+`
+	want := []Monitor{
+		{Name: "DP-1", Width: 3840, Height: 2160, Refresh: 59.997002},
+		{Name: "HDMI-A-1", Width: 1920, Height: 1080, Refresh: 60},
+	}
+	if got := parseWlrRandr(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWlrRandr() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDRMMonitors(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"class/drm/card0-eDP-1/status":     "connected\n",
+		"class/drm/card0-eDP-1/modes":      "2256x1504\n1920x1200\n",
+		"class/drm/card0-HDMI-A-1/status":  "disconnected\n",
+		"class/drm/card0-HDMI-A-1/modes":   "",
+		"class/drm/card1-DP-2/status":      "connected\n",
+		"class/drm/card1-DP-2/modes":       "3840x2160\n",
+		"class/drm/card1-DP-3/status":      "connected\n",
+		"class/drm/card1-DP-3/modes":       "",
+		"class/drm/card0/dev":              "226:0\n",
+		"class/drm/renderD128/dev":         "226:128\n",
+		"class/drm/card1-Writeback-1/dpms": "On\n",
+	})
+	want := []Monitor{
+		{Name: "eDP-1", Width: 2256, Height: 1504},
+		{Name: "DP-2", Width: 3840, Height: 2160},
+	}
+	if got := drmMonitors(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("drmMonitors() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMonitorString(t *testing.T) {
+	if got := (Monitor{Width: 2560, Height: 1440, Refresh: 143.97}).String(); got != "2560x1440 @ 144Hz" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (Monitor{Width: 1920, Height: 1080}).String(); got != "1920x1080" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestResolutionEmptyOverSSH(t *testing.T) {
+	t.Setenv("DISPLAY", "localhost:10.0")
+	t.Setenv("SSH_CONNECTION", "203.0.113.7 51234 198.51.100.1 22")
+	t.Setenv("SSH_TTY", "")
+
+	m, _ := Lookup("resolution")
+	value, err := m.Collect(context.Background())
+	if err != nil || value.Text != "" || len(value.Items) != 0 {
+		t.Errorf("resolution over ssh -X = %+v, %v, want nothing", value, err)
+	}
+}
@@ -0,0 +1,165 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	Register(NewModule("shell", "Shell", func(ctx context.Context) (Value, error) {
+		name, path := parentShell(procRoot, os.Getppid())
+		if name == "" {
+			return Value{}, nil
+		}
+		if version := shellVersion(ctx, name, path); version != "" {
+			return Value{Text: name + " " + version}, nil
+		}
+		return Value{Text: name}, nil
+	}))
+}
+
+// cacheDir holds small caches such as shell versions; empty disables them.
+var cacheDir string
+
+// SetCacheDir sets the directory collectors may cache results in.
+func SetCacheDir(dir string) {
+	cacheDir = dir
+}
+
+// shells are the process names recognised as shells.
+var shells = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "fish": true, "dash": true,
+	"ksh": true, "mksh": true, "tcsh": true, "csh": true, "nu": true,
+	"elvish": true, "xonsh": true, "ion": true, "oil": true, "osh": true,
+	"pwsh": true, "yash": true,
+}
+
+// parentShell returns the name and binary of the nearest ancestor shell,
+// which is the shell actually running anifetch rather than the login
+// shell. It falls back to $SHELL where there is no procfs.
+func parentShell(root string, pid int) (string, string) {
+	for _, p := range processAncestors(root, pid) {
+		// Login shells are named with a leading dash, e.g. "-zsh"
+		name := strings.TrimPrefix(p.Comm, "-")
+		if shells[name] {
+			exe, _ := os.Readlink(filepath.Join(root, strconv.Itoa(p.PID), "exe"))
+			return name, exe
+		}
+	}
+
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return filepath.Base(shell), shell
+	}
+	return "", ""
+}
+
+// shellVersionEnv lists variables some shells export with their version.
+var shellVersionEnv = map[string]string{
+	"bash": "BASH_VERSION",
+	"zsh":  "ZSH_VERSION",
+	"fish": "FISH_VERSION",
+}
+
+var versionNumber = regexp.MustCompile(`[0-9]+(\.[0-9]+)+`)
+
+// shellVersionTimeout bounds running "<shell> --version".
+const shellVersionTimeout = 300 * time.Millisecond
+
+// shellVersion returns the version of the shell at path, from the
+// environment when exported, then from the cache, and finally by running
+// the shell with --version. A shell without a version yields "".
+func shellVersion(ctx context.Context, name, path string) string {
+	if env, ok := shellVersionEnv[name]; ok {
+		if v := versionNumber.FindString(os.Getenv(env)); v != "" {
+			return v
+		}
+	}
+	if path == "" || name == "dash" || name == "sh" {
+		// dash and POSIX sh have no --version
+		return ""
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	key := path
+	mtime := stat.ModTime().UnixNano()
+	if v, ok := cachedShellVersion(key, mtime); ok {
+		return v
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, shellVersionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if ctx.Err() != nil {
+		// Slow this time; try again on the next run
+		return ""
+	}
+	version := ""
+	if err == nil {
+		firstLine, _, _ := strings.Cut(string(out), "\n")
+		version = versionNumber.FindString(firstLine)
+	}
+	// Shells that reject --version are remembered too, so they are not
+	// run again until the binary changes
+	storeShellVersion(key, mtime, version)
+	return version
+}
+
+// shellVersionEntry is one record of the shell version cache, valid while
+// the binary's modification time is unchanged.
+type shellVersionEntry struct {
+	ModTime int64  `json:"mod_time"`
+	Version string `json:"version"`
+}
+
+var shellCacheMu sync.Mutex
+
+func shellCachePath() string {
+	return filepath.Join(cacheDir, "shell-versions.json")
+}
+
+func readShellCache() map[string]shellVersionEntry {
+	entries := map[string]shellVersionEntry{}
+	if data, err := os.ReadFile(shellCachePath()); err == nil {
+		json.Unmarshal(data, &entries)
+	}
+	return entries
+}
+
+func cachedShellVersion(path string, mtime int64) (string, bool) {
+	if cacheDir == "" {
+		return "", false
+	}
+	shellCacheMu.Lock()
+	defer shellCacheMu.Unlock()
+
+	entry, ok := readShellCache()[path]
+	if !ok || entry.ModTime != mtime {
+		return "", false
+	}
+	return entry.Version, true
+}
+
+func storeShellVersion(path string, mtime int64, version string) {
+	if cacheDir == "" {
+		return
+	}
+	shellCacheMu.Lock()
+	defer shellCacheMu.Unlock()
+
+	entries := readShellCache()
+	entries[path] = shellVersionEntry{ModTime: mtime, Version: version}
+	if data, err := json.Marshal(entries); err == nil {
+		os.WriteFile(shellCachePath(), data, 0644)
+	}
+}
@@ -0,0 +1,121 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestParentShell(t *testing.T) {
+	t.Run("nearest shell", func(t *testing.T) {
+		root := fakeProcChain(t, []procEntry{
+			{100, 90, "vim"},
+			{90, 80, "-zsh"},
+			{80, 70, "bash"},
+			{70, 1, "kitty"},
+		})
+		if err := os.Symlink("/usr/bin/zsh", filepath.Join(root, "90/exe")); err != nil {
+			t.Fatal(err)
+		}
+		name, path := parentShell(root, 100)
+		if name != "zsh" || path != "/usr/bin/zsh" {
+			t.Errorf("parentShell() = %q, %q, want zsh, /usr/bin/zsh", name, path)
+		}
+	})
+
+	t.Run("SHELL fallback", func(t *testing.T) {
+		root := fakeProcChain(t, []procEntry{{100, 1, "vim"}})
+		t.Setenv("SHELL", "/bin/fish")
+		name, path := parentShell(root, 100)
+		if name != "fish" || path != "/bin/fish" {
+			t.Errorf("parentShell() = %q, %q, want fish, /bin/fish", name, path)
+		}
+	})
+
+	t.Run("no shell", func(t *testing.T) {
+		t.Setenv("SHELL", "")
+		if name, path := parentShell(t.TempDir(), 100); name != "" || path != "" {
+			t.Errorf("parentShell() = %q, %q, want nothing", name, path)
+		}
+	})
+}
+
+func TestShellVersionEnv(t *testing.T) {
+	t.Setenv("BASH_VERSION", "5.2.21(1)-release")
+	t.Setenv("ZSH_VERSION", "")
+	setRoot(t, &cacheDir, "")
+
+	if got := shellVersion(context.Background(), "bash", ""); got != "5.2.21" {
+		t.Errorf("shellVersion(bash) = %q, want 5.2.21", got)
+	}
+	// Not exported and no binary to ask
+	if got := shellVersion(context.Background(), "zsh", ""); got != "" {
+		t.Errorf("shellVersion(zsh) = %q, want empty", got)
+	}
+}
+
+// writeShell writes an executable script standing in for a shell, with
+// the given modification time.
+func writeShell(t *testing.T, path, script string, mtime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShellVersionCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs /bin/sh")
+	}
+	setRoot(t, &cacheDir, t.TempDir())
+	ctx := context.Background()
+	shell := filepath.Join(t.TempDir(), "mksh")
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	writeShell(t, shell, `echo "@(#)MIRBSD KSH R59 2020/10/31 1.2.3"`, mtime)
+	if got := shellVersion(ctx, "mksh", shell); got != "1.2.3" {
+		t.Fatalf("shellVersion() = %q, want 1.2.3", got)
+	}
+
+	// Same mtime: served from the cache without running the binary
+	writeShell(t, shell, `echo "mksh 9.9.9"`, mtime)
+	if got := shellVersion(ctx, "mksh", shell); got != "1.2.3" {
+		t.Errorf("shellVersion() on a cache hit = %q, want 1.2.3", got)
+	}
+
+	// A new mtime means the shell was upgraded
+	writeShell(t, shell, `echo "mksh 9.9.9"`, mtime.Add(time.Minute))
+	if got := shellVersion(ctx, "mksh", shell); got != "9.9.9" {
+		t.Errorf("shellVersion() on a cache miss = %q, want 9.9.9", got)
+	}
+}
+
+func TestShellVersionCachesFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs /bin/sh")
+	}
+	setRoot(t, &cacheDir, t.TempDir())
+	ctx := context.Background()
+	shell := filepath.Join(t.TempDir(), "ash")
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	writeShell(t, shell, `echo "ash: unknown option --version" >&2; exit 2`, mtime)
+	if got := shellVersion(ctx, "ash", shell); got != "" {
+		t.Fatalf("shellVersion() = %q, want empty", got)
+	}
+	if _, ok := cachedShellVersion(shell, mtime.UnixNano()); !ok {
+		t.Fatal("a shell rejecting --version was not cached")
+	}
+
+	// Still cached, so the now working binary is not run
+	writeShell(t, shell, `echo "ash 1.36.1"`, mtime)
+	if got := shellVersion(ctx, "ash", shell); got != "" {
+		t.Errorf("shellVersion() = %q, want the cached empty version", got)
+	}
+}
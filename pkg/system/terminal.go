@@ -0,0 +1,264 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	Register(NewModule("terminal", "Terminal", func(ctx context.Context) (Value, error) {
+		t := DetectTerminal()
+		return Value{Text: strings.TrimSpace(t.Name + " " + t.Version), Data: t}, nil
+	}))
+	Register(NewModule("terminalfont", "Terminal Font", func(ctx context.Context) (Value, error) {
+		return Value{Text: DetectTerminal().Font}, nil
+	}))
+}
+
+// Terminal describes the terminal emulator anifetch is running in.
+type Terminal struct {
+	// Name is a display name such as "kitty" or "WezTerm"
+	Name string `json:"name"`
+	// Version is set when the terminal advertises it
+	Version string `json:"version,omitempty"`
+	// Font is the configured font, for terminals whose config is known
+	Font string `json:"font,omitempty"`
+}
+
+// terminalNames maps process names, as truncated to 15 characters in
+// /proc/<pid>/comm, and TERM_PROGRAM values to display names. Only these
+// are accepted when walking up the process tree.
+var terminalNames = map[string]string{
+	"kitty":           "kitty",
+	"alacritty":       "Alacritty",
+	"foot":            "foot",
+	"footclient":      "foot",
+	"wezterm-gui":     "WezTerm",
+	"WezTerm":         "WezTerm",
+	"ghostty":         "Ghostty",
+	"gnome-terminal-": "GNOME Terminal",
+	"kgx":             "GNOME Console",
+	"konsole":         "Konsole",
+	"xfce4-terminal":  "Xfce Terminal",
+	"mate-terminal":   "MATE Terminal",
+	"lxterminal":      "LXTerminal",
+	"qterminal":       "QTerminal",
+	"terminology":     "Terminology",
+	"yakuake":         "Yakuake",
+	"guake":           "Guake",
+	"tilda":           "Tilda",
+	"sakura":          "Sakura",
+	"tilix":           "Tilix",
+	"terminator":      "Terminator",
+	"xterm":           "xterm",
+	"urxvt":           "urxvt",
+	"urxvtd":          "urxvt",
+	"rxvt":            "rxvt",
+	"st":              "st",
+	"iTerm.app":       "iTerm2",
+	"Apple_Terminal":  "Terminal.app",
+	"vscode":          "VS Code",
+	"code":            "VS Code",
+	"Hyper":           "Hyper",
+	"contour":         "Contour",
+	"rio":             "Rio",
+	"warp":            "Warp",
+	"WarpTerminal":    "Warp",
+}
+
+// DetectTerminal identifies the terminal emulator from TERM_PROGRAM or by
+// walking up the process tree, and reads its configured font.
+func DetectTerminal() Terminal {
+	var t Terminal
+	program := os.Getenv("TERM_PROGRAM")
+	if program != "" && program != "tmux" && program != "screen" {
+		t.Name = prettyTerminal(program)
+		t.Version = os.Getenv("TERM_PROGRAM_VERSION")
+	} else {
+		t.Name = terminalFromProcesses(procRoot, os.Getppid())
+	}
+
+	if t.Name == "" {
+		// Over SSH or inside a detached multiplexer there is no emulator
+		// to find, so fall back to the terminal type
+		t.Name = os.Getenv("TERM")
+	}
+	t.Font = terminalFont(t.Name)
+	return t
+}
+
+func prettyTerminal(name string) string {
+	if pretty, ok := terminalNames[name]; ok {
+		return pretty
+	}
+	return name
+}
+
+// terminalFromProcesses returns the first ancestor of pid that is a known
+// terminal emulator. Shells, multiplexers and other programs in between
+// are skipped. It returns "" at an SSH server or if the chain ends first.
+func terminalFromProcesses(root string, pid int) string {
+	for _, p := range processAncestors(root, pid) {
+		if p.Comm == "sshd" || p.Comm == "sshd-session" {
+			return ""
+		}
+		if name, ok := terminalNames[p.Comm]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// terminalFont reads the font from the config file of known terminals.
+func terminalFont(name string) string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	switch name {
+	case "kitty":
+		return kittyFont(filepath.Join(configDir, "kitty/kitty.conf"))
+	case "Alacritty":
+		if font := alacrittyFont(filepath.Join(configDir, "alacritty/alacritty.toml")); font != "" {
+			return font
+		}
+		return alacrittyFont(filepath.Join(configDir, "alacritty/alacritty.yml"))
+	case "foot":
+		return footFont(filepath.Join(configDir, "foot/foot.ini"))
+	case "WezTerm":
+		if font := weztermFont(filepath.Join(configDir, "wezterm/wezterm.lua")); font != "" {
+			return font
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			return weztermFont(filepath.Join(home, ".wezterm.lua"))
+		}
+	}
+	return ""
+}
+
+// configLines returns the non-comment lines of a config file.
+func configLines(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "--") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// kittyFont reads "font_family Name" and "font_size 11.0".
+func kittyFont(path string) string {
+	family, size := "", ""
+	for _, line := range configLines(path) {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "font_family":
+			family = strings.TrimSpace(value)
+		case "font_size":
+			size = strings.TrimSpace(value)
+		}
+	}
+	return joinFont(family, size)
+}
+
+var (
+	tomlFamily   = regexp.MustCompile(`^family\s*[=:]\s*["']?([^"']+)["']?`)
+	tomlSize     = regexp.MustCompile(`^size\s*[=:]\s*([0-9.]+)`)
+	inlineFamily = regexp.MustCompile(`family\s*=\s*"([^"]+)"`)
+)
+
+// alacrittyFont reads the normal font family and size from alacritty.toml
+// or the older YAML config, whose keys look the same line by line.
+func alacrittyFont(path string) string {
+	family, size := "", ""
+	section := ""
+	for _, line := range configLines(path) {
+		switch {
+		case strings.HasPrefix(line, "["):
+			section = strings.Trim(line, "[]")
+		case strings.HasSuffix(line, ":"):
+			section = strings.TrimSuffix(line, ":")
+		case tomlFamily.MatchString(line) && family == "" && (section == "font.normal" || section == "normal"):
+			family = tomlFamily.FindStringSubmatch(line)[1]
+		case tomlSize.MatchString(line) && (section == "font" || size == ""):
+			size = tomlSize.FindStringSubmatch(line)[1]
+		case strings.HasPrefix(line, "normal") && strings.Contains(line, "family"):
+			// Inline table: normal = { family = "JetBrains Mono" }
+			if m := inlineFamily.FindStringSubmatch(line); m != nil {
+				family = m[1]
+			}
+		}
+	}
+	return joinFont(family, size)
+}
+
+// footFont reads "font=Name:size=11" from the [main] section.
+func footFont(path string) string {
+	section := "main"
+	for _, line := range configLines(path) {
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section != "main" || strings.TrimSpace(key) != "font" {
+			continue
+		}
+		// Several comma-separated fallback fonts may be listed
+		first, _, _ := strings.Cut(strings.TrimSpace(value), ",")
+		family, attrs, _ := strings.Cut(first, ":")
+		size := ""
+		for _, attr := range strings.Split(attrs, ":") {
+			if v, ok := strings.CutPrefix(attr, "size="); ok {
+				size = v
+			}
+		}
+		return joinFont(family, size)
+	}
+	return ""
+}
+
+var (
+	weztermFamily = regexp.MustCompile(`font\s*=\s*wezterm\.font(?:_with_fallback)?\s*\(?\s*\{?\s*["']([^"']+)["']`)
+	weztermSize   = regexp.MustCompile(`font_size\s*=\s*([0-9.]+)`)
+)
+
+// weztermFont finds font = wezterm.font("Name") and font_size in a Lua
+// config without evaluating it.
+func weztermFont(path string) string {
+	family, size := "", ""
+	for _, line := range configLines(path) {
+		if m := weztermFamily.FindStringSubmatch(line); m != nil && family == "" {
+			family = m[1]
+		}
+		if m := weztermSize.FindStringSubmatch(line); m != nil {
+			size = m[1]
+		}
+	}
+	return joinFont(family, size)
+}
+
+func joinFont(family, size string) string {
+	family = strings.TrimSpace(family)
+	if family == "" {
+		return ""
+	}
+	if size != "" {
+		return family + " (" + size + "pt)"
+	}
+	return family
+}
@@ -0,0 +1,102 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// procEntry is one process of a fake /proc tree.
+type procEntry struct {
+	pid, ppid int
+	comm      string
+}
+
+// fakeProcChain writes /proc/<pid>/stat files for the given processes.
+func fakeProcChain(t *testing.T, procs []procEntry) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{}
+	for _, p := range procs {
+		files[fmt.Sprintf("%d/stat", p.pid)] = fmt.Sprintf("%d (%s) S %d %d %d 0 -1 4194560\n", p.pid, p.comm, p.ppid, p.pid, p.pid)
+	}
+	writeFiles(t, root, files)
+	return root
+}
+
+func TestTerminalFromProcesses(t *testing.T) {
+	tests := []struct {
+		name  string
+		procs []procEntry
+		want  string
+	}{
+		{"shell in kitty", []procEntry{
+			{100, 90, "zsh"},
+			{90, 1, "kitty"},
+		}, "kitty"},
+		{"unknown programs are skipped", []procEntry{
+			{100, 90, "bash"},
+			{90, 80, "vim"},
+			{80, 70, "fish"},
+			{70, 1, "gnome-terminal-"},
+		}, "GNOME Terminal"},
+		{"comm with spaces", []procEntry{
+			{100, 90, "bash"},
+			{90, 80, "tmux: client"},
+			{80, 70, "zsh"},
+			{70, 1, "foot"},
+		}, "foot"},
+		{"comm with parentheses", []procEntry{
+			{100, 90, "node (x) y)"},
+			{90, 80, "(sd-pam)"},
+			{80, 1, "alacritty"},
+		}, "Alacritty"},
+		{"over ssh", []procEntry{
+			{100, 90, "bash"},
+			{90, 80, "sshd-session"},
+			{80, 70, "sshd"},
+			{70, 1, "xterm"},
+		}, ""},
+		{"no terminal", []procEntry{
+			{100, 90, "vim"},
+			{90, 1, "bash"},
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := fakeProcChain(t, tt.procs)
+			if got := terminalFromProcesses(root, 100); got != tt.want {
+				t.Errorf("terminalFromProcesses() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectTerminalFallsBackToTerm(t *testing.T) {
+	setRoot(t, &procRoot, fakeProcChain(t, []procEntry{
+		{os.Getppid(), 1, "vim"},
+	}))
+	t.Setenv("TERM_PROGRAM", "")
+	t.Setenv("TERM", "xterm-256color")
+
+	if got := DetectTerminal().Name; got != "xterm-256color" {
+		t.Errorf("DetectTerminal().Name = %q, want %q", got, "xterm-256color")
+	}
+}
+
+func TestTerminalModuleData(t *testing.T) {
+	t.Setenv("TERM_PROGRAM", "WezTerm")
+	t.Setenv("TERM_PROGRAM_VERSION", "20240203-110809-5046fc22")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	report := Collect(context.Background(), []string{"terminal"}, Options{})
+	want := Terminal{Name: "WezTerm", Version: "20240203-110809-5046fc22"}
+	if got := report.Data("terminal"); got != want {
+		t.Errorf("terminal data = %#v, want %#v", got, want)
+	}
+	if got := report.Text("terminal"); got != "WezTerm 20240203-110809-5046fc22" {
+		t.Errorf("terminal = %q", got)
+	}
+}
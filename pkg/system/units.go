@@ -0,0 +1,113 @@
+package system
+
+import (
+	"fmt"
+	"math"
+)
+
+// Units lists the accepted byte units; auto picks the largest unit that
+// keeps the value at or above one.
+var Units = []string{"auto", "MiB", "GiB"}
+
+// Format controls how byte quantities are displayed.
+type Format struct {
+	// Unit is one of Units
+	Unit string
+	// SI uses powers of 1000 (MB, GB) instead of 1024 (MiB, GiB)
+	SI bool
+	// Percent appends the used percentage, e.g. "(49%)"
+	Percent bool
+}
+
+var format = Format{Unit: "auto", Percent: true}
+
+// SetFormat changes how byte quantities are displayed.
+func SetFormat(f Format) {
+	format = f
+}
+
+// Percentage is implemented by module data that is a share of a whole,
+// which the display can draw as a usage bar.
+type Percentage interface {
+	Percentage() float64
+}
+
+// Percent is a bare percentage, such as CPU load.
+type Percent float64
+
+func (p Percent) Percentage() float64 { return float64(p) }
+
+// Usage is an amount used out of a total, such as memory or disk space.
+type Usage struct {
+	Used    uint64  `json:"used_bytes"`
+	Total   uint64  `json:"total_bytes"`
+	Percent float64 `json:"percent"`
+}
+
+// NewUsage returns the usage of used bytes out of total.
+func NewUsage(used, total uint64) Usage {
+	u := Usage{Used: used, Total: total}
+	if total > 0 {
+		u.Percent = 100 * float64(used) / float64(total)
+	}
+	return u
+}
+
+func (u Usage) Percentage() float64 { return u.Percent }
+
+// String formats the usage according to the current Format, e.g.
+// "7.64 GiB / 15.52 GiB (49%)". Both amounts use the unit picked for
+// Total so they are easy to compare.
+func (u Usage) String() string {
+	exp := byteExponent(u.Total, format)
+	s := formatBytesExp(u.Used, exp, format) + " / " + formatBytesExp(u.Total, exp, format)
+	if format.Percent && u.Total > 0 {
+		s += fmt.Sprintf(" (%.0f%%)", u.Percent)
+	}
+	return s
+}
+
+// formatBytes renders n bytes in the unit chosen by f.
+func formatBytes(n uint64, f Format) string {
+	return formatBytesExp(n, byteExponent(n, f), f)
+}
+
+// byteExponent returns the power of the base that f's unit stands for,
+// or in auto mode the largest one that keeps n at or above one.
+func byteExponent(n uint64, f Format) int {
+	switch f.Unit {
+	case "MiB":
+		return 2
+	case "GiB":
+		return 3
+	}
+	base := 1024.0
+	if f.SI {
+		base = 1000
+	}
+	exp := 0
+	for v := float64(n); v >= base && exp < len(unitNames)-1; v /= base {
+		exp++
+	}
+	return exp
+}
+
+var (
+	unitNames   = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	siUnitNames = []string{"B", "kB", "MB", "GB", "TB", "PB"}
+)
+
+// formatBytesExp renders n bytes in the unit base^exp.
+func formatBytesExp(n uint64, exp int, f Format) string {
+	base, names := 1024.0, unitNames
+	if f.SI {
+		base, names = 1000, siUnitNames
+	}
+
+	v := float64(n) / math.Pow(base, float64(exp))
+	if exp <= 2 {
+		// Nobody needs fractional megabytes
+		return fmt.Sprintf("%.0f %s", v, names[exp])
+	}
+	return fmt.Sprintf("%.2f %s", v, names[exp])
+}
@@ -0,0 +1,52 @@
+package system
+
+import "testing"
+
+func TestUsageString(t *testing.T) {
+	const (
+		mib = 1 << 20
+		gib = 1 << 30
+	)
+	tests := []struct {
+		name  string
+		f     Format
+		usage Usage
+		want  string
+	}{
+		{"auto uses the total's unit", Format{Unit: "auto"}, NewUsage(558*mib, 6012*mib), "0.54 GiB / 5.87 GiB"},
+		{"auto in MiB", Format{Unit: "auto", Percent: true}, NewUsage(200*mib, 512*mib), "200 MiB / 512 MiB (39%)"},
+		{"fixed MiB", Format{Unit: "MiB"}, NewUsage(2*gib, 4*gib), "2048 MiB / 4096 MiB"},
+		{"fixed GiB", Format{Unit: "GiB"}, NewUsage(512*mib, 4*gib), "0.50 GiB / 4.00 GiB"},
+		{"SI", Format{Unit: "auto", SI: true}, NewUsage(500_000_000, 2_000_000_000), "0.50 GB / 2.00 GB"},
+		{"empty", Format{Unit: "auto", Percent: true}, NewUsage(0, 0), "0 B / 0 B"},
+	}
+	old := format
+	t.Cleanup(func() { SetFormat(old) })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetFormat(tt.f)
+			if got := tt.usage.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		f    Format
+		want string
+	}{
+		{0, Format{Unit: "auto"}, "0 B"},
+		{1023, Format{Unit: "auto"}, "1023 B"},
+		{1536, Format{Unit: "auto"}, "2 KiB"},
+		{3 << 30, Format{Unit: "auto"}, "3.00 GiB"},
+		{1_500_000, Format{Unit: "auto", SI: true}, "2 MB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n, tt.f); got != tt.want {
+			t.Errorf("formatBytes(%d, %+v) = %q, want %q", tt.n, tt.f, got, tt.want)
+		}
+	}
+}
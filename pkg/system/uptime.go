@@ -0,0 +1,53 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(NewModule("kernel", "Kernel", func(ctx context.Context) (Value, error) {
+		release, err := kernelRelease()
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{Text: release}, nil
+	}))
+	Register(NewModule("uptime", "Uptime", func(ctx context.Context) (Value, error) {
+		uptime, err := systemUptime()
+		if err != nil {
+			return Value{}, err
+		}
+		return Value{Text: formatUptime(uptime)}, nil
+	}))
+}
+
+// formatUptime renders a duration like "2 days, 3 hours, 5 mins", leaving
+// out zero units.
+func formatUptime(d time.Duration) string {
+	total := int(d / time.Minute)
+	days := total / (24 * 60)
+	hours := total / 60 % 24
+	mins := total % 60
+
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, plural(days, "day"))
+	}
+	if hours > 0 {
+		parts = append(parts, plural(hours, "hour"))
+	}
+	if mins > 0 || len(parts) == 0 {
+		parts = append(parts, plural(mins, "min"))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,18 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package system
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// systemUptime measures the time since kern.boottime.
+func systemUptime() (time.Duration, error) {
+	tv, err := unix.SysctlTimeval("kern.boottime")
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(time.Unix(tv.Unix())), nil
+}
@@ -0,0 +1,7 @@
+package system
+
+import "time"
+
+func systemUptime() (time.Duration, error) {
+	return readProcUptime(procRoot)
+}
@@ -0,0 +1,25 @@
+package system
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatUptime(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0 mins"},
+		{59 * time.Second, "0 mins"},
+		{time.Minute, "1 min"},
+		{24 * time.Hour, "1 day"},
+		{2*24*time.Hour + 3*time.Hour + 5*time.Minute, "2 days, 3 hours, 5 mins"},
+		{time.Hour + time.Minute, "1 hour, 1 min"},
+	}
+	for _, tt := range tests {
+		if got := formatUptime(tt.d); got != tt.want {
+			t.Errorf("formatUptime(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
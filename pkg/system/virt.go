@@ -0,0 +1,181 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register(NewModule("virt", "Virtualization", func(ctx context.Context) (Value, error) {
+		v := detectVirtualization(rootDir, procRoot, sysRoot)
+		return Value{Text: v.String(), Data: v}, nil
+	}))
+}
+
+// Virtualization describes the container and hypervisor anifetch runs in;
+// both are empty on bare metal.
+type Virtualization struct {
+	// Container is e.g. "Docker", "Podman", "LXC" or "systemd-nspawn"
+	Container string `json:"container,omitempty"`
+	// Hypervisor is e.g. "KVM", "VMware", "Hyper-V", "WSL2", or "VM" when
+	// only the CPU's hypervisor flag gives it away
+	Hypervisor string `json:"hypervisor,omitempty"`
+}
+
+// String formats the environment like "Docker on KVM".
+func (v Virtualization) String() string {
+	switch {
+	case v.Container != "" && v.Hypervisor != "":
+		return v.Container + " on " + v.Hypervisor
+	case v.Container != "":
+		return v.Container
+	}
+	return v.Hypervisor
+}
+
+func detectVirtualization(root, procRoot, sysRoot string) Virtualization {
+	v := Virtualization{Container: detectContainer(root, procRoot)}
+	if wsl := detectWSL(procRoot); wsl != "" {
+		v.Hypervisor = wsl
+		// WSL's init sets container=wsl; the kernel already says as much
+		if v.Container == "WSL" {
+			v.Container = ""
+		}
+	} else {
+		v.Hypervisor = detectHypervisor(procRoot, sysRoot)
+	}
+	return v
+}
+
+// containerNames maps values of the "container" environment variable, set
+// by container managers for PID 1, to display names.
+var containerNames = map[string]string{
+	"docker":         "Docker",
+	"podman":         "Podman",
+	"oci":            "OCI",
+	"lxc":            "LXC",
+	"lxc-libvirt":    "LXC",
+	"systemd-nspawn": "systemd-nspawn",
+	"wsl":            "WSL",
+}
+
+// detectContainer checks the marker files Docker and Podman create, the
+// "container" variable, and the cgroup path of PID 1.
+func detectContainer(root, procRoot string) string {
+	switch {
+	case exists(filepath.Join(root, "run/.containerenv")):
+		return "Podman"
+	case exists(filepath.Join(root, ".dockerenv")):
+		return "Docker"
+	}
+
+	container := os.Getenv("container")
+	if container == "" {
+		// PID 1's environment is only readable by root
+		if data, err := os.ReadFile(filepath.Join(procRoot, "1/environ")); err == nil {
+			for _, entry := range strings.Split(string(data), "\x00") {
+				if value, ok := strings.CutPrefix(entry, "container="); ok {
+					container = value
+				}
+			}
+		}
+	}
+	if container != "" {
+		if name, ok := containerNames[container]; ok {
+			return name
+		}
+		return container
+	}
+
+	if data, err := os.ReadFile(filepath.Join(procRoot, "1/cgroup")); err == nil {
+		cgroup := string(data)
+		switch {
+		case strings.Contains(cgroup, "kubepods"):
+			return "Kubernetes"
+		case strings.Contains(cgroup, "docker"):
+			return "Docker"
+		case strings.Contains(cgroup, "libpod"):
+			return "Podman"
+		case strings.Contains(cgroup, "/lxc"):
+			return "LXC"
+		}
+	}
+	return ""
+}
+
+// detectWSL recognises the Microsoft kernels of WSL, whose release reads
+// e.g. "5.15.153.1-microsoft-standard-WSL2" (WSL 1 reports "Microsoft").
+func detectWSL(procRoot string) string {
+	data, err := os.ReadFile(filepath.Join(procRoot, "version"))
+	if err != nil {
+		return ""
+	}
+	version := string(data)
+	switch {
+	case strings.Contains(version, "WSL2"), strings.Contains(version, "microsoft-standard"):
+		return "WSL2"
+	case strings.Contains(version, "Microsoft"):
+		return "WSL"
+	}
+	return ""
+}
+
+// dmiHypervisors maps substrings of the DMI vendor or product to the
+// hypervisor that sets them.
+var dmiHypervisors = []struct{ match, name string }{
+	{"KVM", "KVM"},
+	{"QEMU", "QEMU"},
+	{"VMware", "VMware"},
+	{"VirtualBox", "VirtualBox"},
+	{"innotek", "VirtualBox"},
+	{"Virtual Machine", "Hyper-V"},
+	{"Xen", "Xen"},
+	{"Parallels", "Parallels"},
+	{"Bochs", "Bochs"},
+	{"Amazon EC2", "Amazon EC2"},
+	{"Google Compute Engine", "Google Compute Engine"},
+	{"OpenStack", "OpenStack"},
+	{"BHYVE", "bhyve"},
+	{"Apple Virtualization", "Apple Virtualization"},
+}
+
+// detectHypervisor reads the DMI strings the hypervisor fills in, then
+// /sys/hypervisor, then the CPU's hypervisor flag.
+func detectHypervisor(procRoot, sysRoot string) string {
+	dmi := readDMI(sysRoot)
+	for _, field := range []string{dmi.SysVendor, dmi.ProductName, dmi.BIOSVendor, dmi.BoardVendor} {
+		for _, h := range dmiHypervisors {
+			if field != "" && strings.Contains(field, h.match) {
+				return h.name
+			}
+		}
+	}
+
+	if hv := readSysfsString(filepath.Join(sysRoot, "hypervisor/type")); hv != "" {
+		if hv == "xen" {
+			return "Xen"
+		}
+		return hv
+	}
+
+	if data, err := os.ReadFile(filepath.Join(procRoot, "cpuinfo")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "flags") {
+				for _, flag := range strings.Fields(line) {
+					if flag == "hypervisor" {
+						return "VM"
+					}
+				}
+				break
+			}
+		}
+	}
+	return ""
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
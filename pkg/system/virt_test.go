@@ -0,0 +1,100 @@
+package system
+
+import "testing"
+
+func TestDetectVirtualization(t *testing.T) {
+	const flags = "processor\t: 0\nflags\t\t: fpu vme de pse tsc msr hypervisor lahf_lm\n"
+	tests := []struct {
+		name string
+		env  string // the "container" variable
+		root map[string]string
+		proc map[string]string
+		sys  map[string]string
+		want Virtualization
+	}{
+		{"bare metal", "", nil, map[string]string{
+			"cpuinfo": "processor\t: 0\nflags\t\t: fpu vme de pse\n",
+			"version": "Linux version 6.9.1-arch1-1\n",
+		}, nil, Virtualization{}},
+		{"dockerenv", "", map[string]string{".dockerenv": ""}, nil, nil,
+			Virtualization{Container: "Docker"}},
+		{"containerenv", "", map[string]string{"run/.containerenv": "engine=\"podman-4.9.3\"\n"}, nil, nil,
+			Virtualization{Container: "Podman"}},
+		{"container variable", "systemd-nspawn", nil, nil, nil,
+			Virtualization{Container: "systemd-nspawn"}},
+		{"unknown container variable", "buildah", nil, nil, nil,
+			Virtualization{Container: "buildah"}},
+		{"PID 1 environ", "", nil, map[string]string{
+			"1/environ": "PATH=/usr/bin\x00container=lxc\x00TERM=linux\x00",
+		}, nil, Virtualization{Container: "LXC"}},
+		{"kubepods cgroup", "", nil, map[string]string{
+			"1/cgroup": "0::/kubepods/besteffort/pod1234/abcd\n",
+		}, nil, Virtualization{Container: "Kubernetes"}},
+		{"docker cgroup", "", nil, map[string]string{
+			"1/cgroup": "12:memory:/docker/0123abcd\n0::/docker/0123abcd\n",
+		}, nil, Virtualization{Container: "Docker"}},
+		{"libpod cgroup", "", nil, map[string]string{
+			"1/cgroup": "0::/machine.slice/libpod-0123abcd.scope\n",
+		}, nil, Virtualization{Container: "Podman"}},
+		{"lxc cgroup", "", nil, map[string]string{
+			"1/cgroup": "0::/lxc.payload.web/init.scope\n",
+		}, nil, Virtualization{Container: "LXC"}},
+		{"DMI vendor", "", nil, nil, map[string]string{
+			"class/dmi/id/sys_vendor":   "QEMU\n",
+			"class/dmi/id/product_name": "Standard PC (Q35 + ICH9, 2009)\n",
+		}, Virtualization{Hypervisor: "QEMU"}},
+		{"DMI product", "", nil, nil, map[string]string{
+			"class/dmi/id/sys_vendor":   "Microsoft Corporation\n",
+			"class/dmi/id/product_name": "Virtual Machine\n",
+		}, Virtualization{Hypervisor: "Hyper-V"}},
+		{"sys hypervisor", "", nil, nil, map[string]string{"hypervisor/type": "xen\n"},
+			Virtualization{Hypervisor: "Xen"}},
+		{"hypervisor flag", "", nil, map[string]string{"cpuinfo": flags}, nil,
+			Virtualization{Hypervisor: "VM"}},
+		{"container in VM", "", map[string]string{".dockerenv": ""}, nil, map[string]string{
+			"class/dmi/id/sys_vendor": "VMware, Inc.\n",
+		}, Virtualization{Container: "Docker", Hypervisor: "VMware"}},
+		{"WSL2", "", nil, map[string]string{
+			"version": "Linux version 5.15.153.1-microsoft-standard-WSL2 (root@1234) (gcc)\n",
+			"cpuinfo": flags,
+		}, nil, Virtualization{Hypervisor: "WSL2"}},
+		{"WSL1", "", nil, map[string]string{
+			"version": "Linux version 4.4.0-19041-Microsoft (Microsoft@Microsoft.com)\n",
+		}, nil, Virtualization{Hypervisor: "WSL"}},
+		{"WSL2 with container=wsl", "wsl", nil, map[string]string{
+			"version": "Linux version 5.15.153.1-microsoft-standard-WSL2\n",
+		}, nil, Virtualization{Hypervisor: "WSL2"}},
+		{"Docker in WSL2", "", map[string]string{".dockerenv": ""}, map[string]string{
+			"version": "Linux version 5.15.153.1-microsoft-standard-WSL2\n",
+		}, nil, Virtualization{Container: "Docker", Hypervisor: "WSL2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("container", tt.env)
+			root, proc, sys := t.TempDir(), t.TempDir(), t.TempDir()
+			writeFiles(t, root, tt.root)
+			writeFiles(t, proc, tt.proc)
+			writeFiles(t, sys, tt.sys)
+			if got := detectVirtualization(root, proc, sys); got != tt.want {
+				t.Errorf("detectVirtualization() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVirtualizationString(t *testing.T) {
+	tests := []struct {
+		v    Virtualization
+		want string
+	}{
+		{Virtualization{}, ""},
+		{Virtualization{Container: "Docker"}, "Docker"},
+		{Virtualization{Hypervisor: "KVM"}, "KVM"},
+		{Virtualization{Container: "Docker", Hypervisor: "KVM"}, "Docker on KVM"},
+	}
+	for _, tt := range tests {
+		if got := tt.v.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}